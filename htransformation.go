@@ -7,20 +7,58 @@ import (
 	"net"
 	"net/http"
 
+	"github.com/tomMoulard/htransformation/pkg/condition"
+	"github.com/tomMoulard/htransformation/pkg/handler/add"
+	"github.com/tomMoulard/htransformation/pkg/handler/clientip"
+	"github.com/tomMoulard/htransformation/pkg/handler/codec"
+	"github.com/tomMoulard/htransformation/pkg/handler/cookie"
+	"github.com/tomMoulard/htransformation/pkg/handler/copier"
 	"github.com/tomMoulard/htransformation/pkg/handler/deleter"
+	"github.com/tomMoulard/htransformation/pkg/handler/deletevalue"
+	"github.com/tomMoulard/htransformation/pkg/handler/echo"
+	"github.com/tomMoulard/htransformation/pkg/handler/forwarded"
+	"github.com/tomMoulard/htransformation/pkg/handler/generateid"
+	"github.com/tomMoulard/htransformation/pkg/handler/hasher"
+	"github.com/tomMoulard/htransformation/pkg/handler/headertourl"
 	"github.com/tomMoulard/htransformation/pkg/handler/join"
+	"github.com/tomMoulard/htransformation/pkg/handler/keep"
+	"github.com/tomMoulard/htransformation/pkg/handler/reject"
 	"github.com/tomMoulard/htransformation/pkg/handler/rename"
+	"github.com/tomMoulard/htransformation/pkg/handler/requirement"
 	"github.com/tomMoulard/htransformation/pkg/handler/rewrite"
 	"github.com/tomMoulard/htransformation/pkg/handler/set"
+	"github.com/tomMoulard/htransformation/pkg/handler/signer"
+	"github.com/tomMoulard/htransformation/pkg/handler/tracecontext"
+	"github.com/tomMoulard/htransformation/pkg/handler/traceconvert"
+	"github.com/tomMoulard/htransformation/pkg/handler/urltoheader"
+	"github.com/tomMoulard/htransformation/pkg/handler/verifier"
 	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
 )
 
 // HeadersTransformation holds the necessary components of a Traefik plugin.
 type HeadersTransformation struct {
 	name         string
 	next         http.Handler
-	reqHandlers  []types.Handler
-	respHandlers []types.Handler
+	reqHandlers  []ruleHandler
+	respHandlers []ruleHandler
+	// requirements are checked before the request rules are applied.
+	requirements []requirementHandler
+	// keepOriginalHeader is true if a rule needs the request headers as received by the plugin.
+	keepOriginalHeader bool
+}
+
+// ruleHandler is a rule handler along with the condition to match for it to be applied.
+type ruleHandler struct {
+	handler types.Handler
+	when    *condition.Matcher
+}
+
+// requirementHandler is a requirement along with the condition to match for it to be checked.
+type requirementHandler struct {
+	requirement types.Requirement
+	when        *condition.Matcher
 }
 
 // Config holds configuration to be passed to the plugin.
@@ -35,18 +73,51 @@ func CreateConfig() *Config {
 	}
 }
 
-// New instantiates and returns the required components used to handle an HTTP request.
-func New(_ context.Context, next http.Handler, config *Config, name string) (http.Handler, error) {
-	handlerBuilder := map[types.RuleType]func(types.Rule) (types.Handler, error){
+// handlerBuilders returns the handler constructors of each rule type.
+func handlerBuilders() map[types.RuleType]func(types.Rule) (types.Handler, error) {
+	return map[types.RuleType]func(types.Rule) (types.Handler, error){
+		types.Add:              add.New,
+		types.Allowlist:        keep.New,
+		types.ClientIP:         clientip.New,
+		types.CookieAttributes: cookie.NewAttributes,
+		types.CookieDelete:     cookie.NewDelete,
+		types.CookieRename:     cookie.NewRename,
+		types.CookieSet:        cookie.NewSet,
+		types.Copy:             copier.New,
+		types.Decode:           codec.NewDecode,
 		types.Delete:           deleter.New,
+		types.DeleteValue:      deletevalue.New,
+		types.Echo:             echo.New,
+		types.Encode:           codec.NewEncode,
+		types.Forwarded:        forwarded.New,
+		types.GenerateID:       generateid.New,
+		types.HMAC:             signer.New,
+		types.Hash:             hasher.New,
+		types.HeaderToURL:      headertourl.New,
 		types.Join:             join.New,
+		types.Keep:             keep.New,
+		types.Reject:           reject.New,
 		types.Rename:           rename.New,
+		types.Require:          requirement.New,
 		types.RewriteValueRule: rewrite.New,
 		types.Set:              set.New,
+		types.SetIfAbsent:      set.NewIfAbsent,
+		types.SetIfPresent:     set.NewIfPresent,
+		types.TraceContext:     tracecontext.New,
+		types.TraceConvert:     traceconvert.New,
+		types.URLToHeader:      urltoheader.New,
+		types.VerifyHMAC:       verifier.New,
 	}
+}
 
-	reqHandlers := make([]types.Handler, 0, len(config.Rules))
-	respHandlers := make([]types.Handler, 0, len(config.Rules))
+// New instantiates and returns the required components used to handle an HTTP request.
+func New(_ context.Context, next http.Handler, config *Config, name string) (http.Handler, error) {
+	reqHandlers := make([]ruleHandler, 0, len(config.Rules))
+	respHandlers := make([]ruleHandler, 0, len(config.Rules))
+	requirements := make([]requirementHandler, 0)
+	keepOriginalHeader := false
+
+	handlerBuilder := handlerBuilders()
 
 	for _, rule := range config.Rules {
 		newHandler, ok := handlerBuilder[rule.Type]
@@ -63,10 +134,25 @@ func New(_ context.Context, next http.Handler, config *Config, name string) (htt
 			return nil, fmt.Errorf("%w: %s", err, rule.Name)
 		}
 
+		when, err := condition.New(rule.When, rule.SetOnResponse)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, rule.Name)
+		}
+
+		if rule.Type == types.Echo && !rule.Transformed {
+			keepOriginalHeader = true
+		}
+
+		if required, ok := handler.(types.Requirement); ok {
+			requirements = append(requirements, requirementHandler{requirement: required, when: when})
+
+			continue
+		}
+
 		if rule.SetOnResponse {
-			respHandlers = append(respHandlers, handler)
+			respHandlers = append(respHandlers, ruleHandler{handler: handler, when: when})
 		} else {
-			reqHandlers = append(reqHandlers, handler)
+			reqHandlers = append(reqHandlers, ruleHandler{handler: handler, when: when})
 		}
 	}
 
@@ -75,32 +161,79 @@ func New(_ context.Context, next http.Handler, config *Config, name string) (htt
 		next:         next,
 		reqHandlers:  reqHandlers,
 		respHandlers: respHandlers,
+		requirements: requirements,
+
+		keepOriginalHeader: keepOriginalHeader,
 	}, nil
 }
 
 // Iterate over every header to match the ones specified in the config and
 // return nothing if regexp failed.
 func (u *HeadersTransformation) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
-	for _, handler := range u.reqHandlers {
-		handler.Handle(responseWriter, request)
+	requestState := &state.State{OriginalHeader: nil, StatusCode: 0}
+	if u.keepOriginalHeader {
+		requestState.OriginalHeader = request.Header.Clone()
 	}
 
-	wrappedResponseWriter := newWrappedResponseWriter(responseWriter, func(rw http.ResponseWriter) {
-		for _, handler := range u.respHandlers {
-			handler.Handle(rw, request)
+	request = request.WithContext(state.NewContext(request.Context(), requestState))
+
+	wrappedResponseWriter := newWrappedResponseWriter(responseWriter, func(rw http.ResponseWriter, statusCode int) {
+		requestState.StatusCode = statusCode
+
+		for _, rule := range u.respHandlers {
+			if rule.when.Match(rw, request) {
+				rule.handler.Handle(rw, request)
+			}
 		}
 	})
 
+	if violations := u.check(wrappedResponseWriter, request); len(violations) > 0 {
+		response.WriteProblem(wrappedResponseWriter, violations)
+
+		return
+	}
+
+	for _, rule := range u.reqHandlers {
+		if rule.when.Match(wrappedResponseWriter, request) {
+			rule.handler.Handle(wrappedResponseWriter, request)
+		}
+
+		// a rule answering the request, e.g. rejecting it, stops the chain.
+		if requestState.StatusCode != 0 {
+			return
+		}
+	}
+
 	u.next.ServeHTTP(wrappedResponseWriter, request)
 }
 
+// check returns the violations of the requirements by the request.
+func (u *HeadersTransformation) check(rw http.ResponseWriter, req *http.Request) []types.Violation {
+	var violations []types.Violation
+
+	for _, required := range u.requirements {
+		if !required.when.Match(rw, req) {
+			continue
+		}
+
+		if violation := required.requirement.Check(req); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	return violations
+}
+
+// responseHandler is called with the status code of the response right before its headers are written.
+type responseHandler func(rw http.ResponseWriter, statusCode int)
+
 type wrappedResponseWriter struct {
 	rw         http.ResponseWriter
-	handler    func(http.ResponseWriter)
+	handler    responseHandler
 	headerSent bool
 }
 
-func newWrappedResponseWriter(rw http.ResponseWriter, handler func(http.ResponseWriter)) http.ResponseWriter {
+func newWrappedResponseWriter(rw http.ResponseWriter, handler responseHandler) http.ResponseWriter {
 	return &wrappedResponseWriter{
 		rw:         rw,
 		handler:    handler,
@@ -108,13 +241,13 @@ func newWrappedResponseWriter(rw http.ResponseWriter, handler func(http.Response
 	}
 }
 
-func (wrw *wrappedResponseWriter) handleResponseHeader() {
+func (wrw *wrappedResponseWriter) handleResponseHeader(statusCode int) {
 	if wrw.headerSent {
 		return
 	}
 
 	wrw.headerSent = true
-	wrw.handler(wrw.rw)
+	wrw.handler(wrw.rw, statusCode)
 }
 
 func (wrw *wrappedResponseWriter) Header() http.Header {
@@ -122,7 +255,7 @@ func (wrw *wrappedResponseWriter) Header() http.Header {
 }
 
 func (wrw *wrappedResponseWriter) Write(p []byte) (int, error) {
-	wrw.handleResponseHeader()
+	wrw.handleResponseHeader(http.StatusOK)
 
 	n, err := wrw.rw.Write(p)
 	if err != nil {
@@ -133,7 +266,14 @@ func (wrw *wrappedResponseWriter) Write(p []byte) (int, error) {
 }
 
 func (wrw *wrappedResponseWriter) WriteHeader(statusCode int) {
-	wrw.handleResponseHeader()
+	// informational responses, but the protocol switch, are followed by the final response the rules apply to.
+	if statusCode >= http.StatusContinue && statusCode < http.StatusOK && statusCode != http.StatusSwitchingProtocols {
+		wrw.rw.WriteHeader(statusCode)
+
+		return
+	}
+
+	wrw.handleResponseHeader(statusCode)
 	wrw.rw.WriteHeader(statusCode)
 }
 
@@ -92,6 +92,35 @@ func TestValidation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid template",
+			config: &plug.Config{
+				Rules: []types.Rule{
+					{
+						Name:   "set rule",
+						Header: "not-empty",
+						Value:  `{{ .Header }}`,
+						Type:   types.Set,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid condition",
+			config: &plug.Config{
+				Rules: []types.Rule{
+					{
+						Name:   "set rule",
+						Header: "not-empty",
+						Value:  "not-empty",
+						Type:   types.Set,
+						When:   &types.Condition{PathRegexp: "("},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -127,6 +156,40 @@ func TestHeaderRules(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "copy rule",
+			rule: types.Rule{
+				Name:   "copy rule",
+				Header: "Referer",
+				Value:  "X-Referer",
+				Type:   types.Copy,
+			},
+			additionalHeader: map[string]string{
+				"Referer": "http://foo.bar",
+			},
+			wantErr: false,
+		},
+		{
+			name: "keep rule",
+			rule: types.Rule{
+				Name:   "keep rule",
+				Values: []string{"Referer"},
+				Type:   types.Keep,
+			},
+			additionalHeader: map[string]string{
+				"Referer": "http://foo.bar",
+			},
+			wantErr: false,
+		},
+		{
+			name: "allowlist rule",
+			rule: types.Rule{
+				Name:   "allowlist rule",
+				Values: []string{"Referer"},
+				Type:   types.Allowlist,
+			},
+			wantErr: false,
+		},
 		{
 			name: "rename rule",
 			rule: types.Rule{
@@ -261,3 +324,602 @@ func TestSetOnResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestWhen(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		rule           types.Rule
+		path           string
+		upstreamStatus int
+		expectedValue  string
+	}{
+		{
+			name: "without condition",
+			rule: types.Rule{
+				Name:   "set rule",
+				Header: "X-Test",
+				Value:  "Tested",
+				Type:   types.Set,
+			},
+			path:          "/foo",
+			expectedValue: "Tested",
+		},
+		{
+			name: "matching condition",
+			rule: types.Rule{
+				Name:   "set rule",
+				Header: "X-Test",
+				Value:  "Tested",
+				Type:   types.Set,
+				When:   &types.Condition{PathPrefix: "/foo"},
+			},
+			path:          "/foo/bar",
+			expectedValue: "Tested",
+		},
+		{
+			name: "not matching condition",
+			rule: types.Rule{
+				Name:   "set rule",
+				Header: "X-Test",
+				Value:  "Tested",
+				Type:   types.Set,
+				When:   &types.Condition{PathPrefix: "/foo"},
+			},
+			path:          "/bar",
+			expectedValue: "",
+		},
+		{
+			name: "matching condition on response",
+			rule: types.Rule{
+				Name:          "set rule",
+				Header:        "X-Test",
+				Value:         "Tested",
+				Type:          types.Set,
+				SetOnResponse: true,
+				When:          &types.Condition{Not: &types.Condition{Methods: []string{http.MethodPost}}},
+			},
+			path:          "/foo",
+			expectedValue: "Tested",
+		},
+		{
+			name: "matching status code",
+			rule: types.Rule{
+				Name:          "set rule",
+				Header:        "Cache-Control",
+				Value:         "no-store",
+				Type:          types.Set,
+				SetOnResponse: true,
+				When:          &types.Condition{StatusCodes: []string{"4xx", "5xx"}},
+			},
+			path:           "/foo",
+			upstreamStatus: http.StatusServiceUnavailable,
+			expectedValue:  "no-store",
+		},
+		{
+			name: "not matching status code",
+			rule: types.Rule{
+				Name:          "set rule",
+				Header:        "Cache-Control",
+				Value:         "no-store",
+				Type:          types.Set,
+				SetOnResponse: true,
+				When:          &types.Condition{StatusCodes: []string{"4xx", "5xx"}},
+			},
+			path:          "/foo",
+			expectedValue: "",
+		},
+		{
+			name: "matching status code written implicitly",
+			rule: types.Rule{
+				Name:          "set rule",
+				Header:        "X-Test",
+				Value:         "Tested",
+				Type:          types.Set,
+				SetOnResponse: true,
+				When:          &types.Condition{StatusCodes: []string{"200"}},
+			},
+			path:           "/foo",
+			upstreamStatus: -1,
+			expectedValue:  "Tested",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := plug.CreateConfig()
+			cfg.Rules = []types.Rule{test.rule}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Request-Test", req.Header.Get("X-Test"))
+
+				switch test.upstreamStatus {
+				case -1:
+					_, err := rw.Write([]byte("ok"))
+					assert.NoError(t, err)
+				case 0:
+					rw.WriteHeader(http.StatusOK)
+				default:
+					rw.WriteHeader(test.upstreamStatus)
+				}
+			})
+
+			handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost"+test.path, nil)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(recorder, req)
+			resp := recorder.Result()
+			require.NoError(t, resp.Body.Close())
+
+			if test.rule.SetOnResponse {
+				assert.Equal(t, test.expectedValue, resp.Header.Get(test.rule.Header))
+			} else {
+				assert.Equal(t, test.expectedValue, resp.Header.Get("X-Request-Test"))
+			}
+		})
+	}
+}
+
+func TestInformationalResponse(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:          "no store on errors",
+			Header:        "Cache-Control",
+			Value:         "no-store",
+			Type:          types.Set,
+			SetOnResponse: true,
+			When:          &types.Condition{StatusCodes: []string{"5xx"}},
+		},
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Link", "</style.css>; rel=preload; as=style")
+		rw.WriteHeader(http.StatusEarlyHints)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/foo", nil)
+	require.NoError(t, err)
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+}
+
+func TestEcho(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		transformed   bool
+		expectedValue string
+	}{
+		{
+			name:          "original request header",
+			transformed:   false,
+			expectedValue: "original",
+		},
+		{
+			name:          "transformed request header",
+			transformed:   true,
+			expectedValue: "transformed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := plug.CreateConfig()
+			cfg.Rules = []types.Rule{
+				{
+					Name:   "set rule",
+					Header: "X-Request-Id",
+					Value:  "transformed",
+					Type:   types.Set,
+				},
+				{
+					Name:          "echo rule",
+					Header:        "^X-Request-Id$",
+					Type:          types.Echo,
+					SetOnResponse: true,
+					Transformed:   test.transformed,
+				},
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost", nil)
+			require.NoError(t, err)
+
+			req.Header.Set("X-Request-Id", "original")
+
+			handler.ServeHTTP(recorder, req)
+			resp := recorder.Result()
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, test.expectedValue, resp.Header.Get("X-Request-Id"))
+		})
+	}
+}
+
+func TestCookies(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:   "cookie delete rule",
+			Cookie: "_ga.*",
+			Type:   types.CookieDelete,
+		},
+		{
+			Name:          "cookie attributes rule",
+			Attributes:    &types.SetCookieAttributes{Secure: true, SameSite: "Lax"},
+			Type:          types.CookieAttributes,
+			SetOnResponse: true,
+		},
+	}
+
+	var requestCookies string
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestCookies = req.Header.Get("Cookie")
+
+		http.SetCookie(rw, &http.Cookie{Name: "session", Value: "1234", Path: "/"})
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	req.Header.Set("Cookie", "_ga=1; session=1234")
+
+	handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "session=1234", requestCookies)
+	assert.Equal(t, "session=1234; Path=/; Secure; SameSite=Lax", resp.Header.Get("Set-Cookie"))
+}
+
+func TestGenerateID(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:           "generate id rule",
+			Header:         "X-Request-Id",
+			Type:           types.GenerateID,
+			CopyToResponse: true,
+		},
+	}
+
+	var requestID string
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestID = req.Header.Get("X-Request-Id")
+
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 36, len(requestID))
+	assert.Equal(t, requestID, resp.Header.Get("X-Request-Id"))
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:          "verify signature",
+			Header:        "X-Signature",
+			Secret:        "s3cr3t",
+			SignedHeaders: []string{"X-User"},
+			StatusCode:    http.StatusForbidden,
+			Type:          types.VerifyHMAC,
+		},
+		{
+			Name:   "set after verification",
+			Header: "X-Verified",
+			Value:  "true",
+			Type:   types.Set,
+		},
+		{
+			Name:          "set on response",
+			Header:        "X-Proxy",
+			Value:         "htransformation",
+			Type:          types.Set,
+			SetOnResponse: true,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		user       string
+		wantNext   bool
+		wantStatus int
+	}{
+		{
+			name:       "valid signature",
+			user:       "alice",
+			wantNext:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid signature",
+			user:       "mallory",
+			wantNext:   false,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			nextCalled := false
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				nextCalled = true
+
+				assert.Equal(t, "true", req.Header.Get("X-Verified"))
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost/foo", nil)
+			require.NoError(t, err)
+
+			req.Header.Set("X-User", test.user)
+			// HMAC-SHA256 of "GET\n/foo\nx-user:alice" with the key "s3cr3t".
+			req.Header.Set("X-Signature", "36d205ea4a9916cba0445ae4de9161b377f568cdc0a2e533534962d61c8bdc73")
+
+			handler.ServeHTTP(recorder, req)
+			resp := recorder.Result()
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, test.wantNext, nextCalled)
+			assert.Equal(t, test.wantStatus, resp.StatusCode)
+			assert.Equal(t, "htransformation", resp.Header.Get("X-Proxy"))
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:       "missing API key",
+			Type:       types.Reject,
+			StatusCode: http.StatusUnauthorized,
+			Body:       "missing API key",
+			When: &types.Condition{
+				Headers: []types.FieldCondition{{Name: "X-Api-Key", Absent: true}},
+			},
+		},
+		{
+			Name: "invalid API key",
+			Type: types.Reject,
+			When: &types.Condition{
+				Not: &types.Condition{
+					Headers: []types.FieldCondition{{Name: "X-Api-Key", Regexp: "^[0-9a-f]{32}$"}},
+				},
+			},
+		},
+		{
+			Name:            "header too large",
+			Type:            types.Reject,
+			StatusCode:      http.StatusRequestHeaderFieldsTooLarge,
+			ResponseHeaders: map[string]string{"X-Reason": "X-Comment"},
+			When: &types.Condition{
+				Headers: []types.FieldCondition{{Name: "X-Comment", LongerThan: 16}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+		wantReason string
+	}{
+		{
+			name:       "valid request",
+			headers:    map[string]string{"X-Api-Key": "0123456789abcdef0123456789abcdef", "X-Comment": "hello"},
+			wantStatus: http.StatusOK,
+			wantBody:   "next",
+		},
+		{
+			name:       "missing header",
+			headers:    map[string]string{},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "missing API key",
+		},
+		{
+			name:       "value not matching",
+			headers:    map[string]string{"X-Api-Key": "secret"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "header too large",
+			headers: map[string]string{
+				"X-Api-Key": "0123456789abcdef0123456789abcdef",
+				"X-Comment": "this comment is too long",
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+			wantReason: "X-Comment",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, err := rw.Write([]byte("next"))
+				assert.NoError(t, err)
+			})
+
+			handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://localhost/foo", nil)
+			require.NoError(t, err)
+
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantStatus, recorder.Code)
+			assert.Equal(t, test.wantBody, recorder.Body.String())
+			assert.Equal(t, test.wantReason, recorder.Header().Get("X-Reason"))
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	cfg := plug.CreateConfig()
+	cfg.Rules = []types.Rule{
+		{
+			Name:   "set API key",
+			Header: "X-Api-Key",
+			Value:  "0123456789abcdef0123456789abcdef",
+			Type:   types.Set,
+		},
+		{
+			Name:   "API key",
+			Header: "X-Api-Key",
+			Value:  "[0-9a-f]{32}",
+			Type:   types.Require,
+		},
+		{
+			Name:   "content type",
+			Header: "Content-Type",
+			Values: []string{"application/json"},
+			Type:   types.Require,
+			When: &types.Condition{
+				Methods: []string{http.MethodPost},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "valid request",
+			method:     http.MethodPost,
+			headers:    map[string]string{"X-Api-Key": "abcdef0123456789abcdef0123456789", "Content-Type": "application/json"},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:       "condition not matching",
+			method:     http.MethodGet,
+			headers:    map[string]string{"X-Api-Key": "abcdef0123456789abcdef0123456789"},
+			wantStatus: http.StatusOK,
+			wantBody:   "0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:       "checked before the request rules",
+			method:     http.MethodPost,
+			headers:    map[string]string{"Content-Type": "text/plain"},
+			wantStatus: http.StatusBadRequest,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"2 invalid request header(s)","invalid-params":[{"name":"X-Api-Key","reason":"missing"},` +
+				`{"name":"Content-Type","reason":"not one of application/json"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, err := rw.Write([]byte(req.Header.Get("X-Api-Key")))
+				assert.NoError(t, err)
+			})
+
+			handler, err := plug.New(t.Context(), next, cfg, "demo-plugin")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req, err := http.NewRequestWithContext(t.Context(), test.method, "http://localhost/foo", nil)
+			require.NoError(t, err)
+
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantStatus, recorder.Code)
+			assert.Equal(t, test.wantBody, recorder.Body.String())
+		})
+	}
+}
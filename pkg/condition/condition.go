@@ -0,0 +1,300 @@
+package condition
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+// Matcher is the compiled form of a types.Condition.
+// A nil Matcher matches every request.
+type Matcher struct {
+	methods      []string
+	hosts        []string
+	pathPrefix   string
+	pathRegexp   *regexp.Regexp
+	query        []fieldMatcher
+	headers      []fieldMatcher
+	statusCodes  []statusRange
+	contentTypes []string
+	and          []*Matcher
+	or           []*Matcher
+	not          *Matcher
+}
+
+type fieldMatcher struct {
+	name       string
+	absent     bool
+	regexp     *regexp.Regexp
+	longerThan int
+}
+
+// New compiles the given condition, it returns a nil Matcher if there is no condition.
+// Conditions on the response are only allowed if onResponse is true.
+func New(cond *types.Condition, onResponse bool) (*Matcher, error) {
+	if cond == nil {
+		return nil, nil //nolint:nilnil // a nil Matcher matches everything.
+	}
+
+	if !onResponse && (len(cond.StatusCodes) > 0 || len(cond.ContentTypes) > 0) {
+		return nil, fmt.Errorf("%w: status codes and content types are response only", types.ErrInvalidCondition)
+	}
+
+	matcher := &Matcher{
+		methods:      make([]string, 0, len(cond.Methods)),
+		hosts:        cond.Hosts,
+		pathPrefix:   cond.PathPrefix,
+		pathRegexp:   nil,
+		query:        nil,
+		headers:      nil,
+		statusCodes:  nil,
+		contentTypes: newContentTypes(cond.ContentTypes),
+		and:          nil,
+		or:           nil,
+		not:          nil,
+	}
+
+	for _, method := range cond.Methods {
+		matcher.methods = append(matcher.methods, strings.ToUpper(method))
+	}
+
+	if cond.PathRegexp != "" {
+		re, err := regexp.Compile(cond.PathRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", types.ErrInvalidRegexp, cond.PathRegexp)
+		}
+
+		matcher.pathRegexp = re
+	}
+
+	if err := matcher.compileFields(cond); err != nil {
+		return nil, err
+	}
+
+	if err := matcher.compileCombinations(cond, onResponse); err != nil {
+		return nil, err
+	}
+
+	return matcher, nil
+}
+
+func (m *Matcher) compileFields(cond *types.Condition) error {
+	var err error
+
+	if m.query, err = newFieldMatchers(cond.Query); err != nil {
+		return err
+	}
+
+	if m.headers, err = newFieldMatchers(cond.Headers); err != nil {
+		return err
+	}
+
+	if m.statusCodes, err = newStatusRanges(cond.StatusCodes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Matcher) compileCombinations(cond *types.Condition, onResponse bool) error {
+	for i := range cond.And {
+		and, err := New(&cond.And[i], onResponse)
+		if err != nil {
+			return err
+		}
+
+		m.and = append(m.and, and)
+	}
+
+	for i := range cond.Or {
+		or, err := New(&cond.Or[i], onResponse)
+		if err != nil {
+			return err
+		}
+
+		m.or = append(m.or, or)
+	}
+
+	not, err := New(cond.Not, onResponse)
+	if err != nil {
+		return err
+	}
+
+	m.not = not
+
+	return nil
+}
+
+func newFieldMatchers(conds []types.FieldCondition) ([]fieldMatcher, error) {
+	matchers := make([]fieldMatcher, 0, len(conds))
+
+	for _, cond := range conds {
+		if cond.Name == "" {
+			return nil, fmt.Errorf("%w: field condition without name", types.ErrMissingRequiredFields)
+		}
+
+		if cond.Absent && (cond.Regexp != "" || cond.LongerThan != 0) {
+			return nil, fmt.Errorf("%w: %s cannot be both absent and match a value", types.ErrInvalidCondition, cond.Name)
+		}
+
+		if cond.LongerThan < 0 {
+			return nil, fmt.Errorf("%w: %s cannot be longer than a negative length", types.ErrInvalidCondition, cond.Name)
+		}
+
+		matcher := fieldMatcher{name: cond.Name, absent: cond.Absent, regexp: nil, longerThan: cond.LongerThan}
+
+		if cond.Regexp != "" {
+			re, err := regexp.Compile(cond.Regexp)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", types.ErrInvalidRegexp, cond.Regexp)
+			}
+
+			matcher.regexp = re
+		}
+
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, nil
+}
+
+// Match reports whether the request, and the response being written if any, matches the condition.
+func (m *Matcher) Match(rw http.ResponseWriter, req *http.Request) bool {
+	if m == nil {
+		return true
+	}
+
+	return m.matchURL(req) && m.matchFields(req) && m.matchResponse(rw, req) && m.matchCombinations(rw, req)
+}
+
+func (m *Matcher) matchURL(req *http.Request) bool {
+	if len(m.methods) > 0 && !m.matchMethod(req.Method) {
+		return false
+	}
+
+	if len(m.hosts) > 0 && !m.matchHost(req.Host) {
+		return false
+	}
+
+	if !strings.HasPrefix(req.URL.Path, m.pathPrefix) {
+		return false
+	}
+
+	return m.pathRegexp == nil || m.pathRegexp.MatchString(req.URL.Path)
+}
+
+func (m *Matcher) matchFields(req *http.Request) bool {
+	query := req.URL.Query()
+	for _, field := range m.query {
+		if !field.match(query[field.name]) {
+			return false
+		}
+	}
+
+	for _, field := range m.headers {
+		if !field.match(header.Values(req, field.name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Matcher) matchCombinations(rw http.ResponseWriter, req *http.Request) bool {
+	for _, and := range m.and {
+		if !and.Match(rw, req) {
+			return false
+		}
+	}
+
+	if len(m.or) > 0 && !m.matchAny(rw, req) {
+		return false
+	}
+
+	return m.not == nil || !m.not.Match(rw, req)
+}
+
+func (m *Matcher) matchAny(rw http.ResponseWriter, req *http.Request) bool {
+	for _, or := range m.or {
+		if or.Match(rw, req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Matcher) matchMethod(method string) bool {
+	for _, expected := range m.methods {
+		if expected == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchHost compares the port of the host only when the expected host has one.
+func (m *Matcher) matchHost(host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	for _, expected := range m.hosts {
+		if _, _, err := net.SplitHostPort(expected); err == nil {
+			if strings.EqualFold(expected, host) {
+				return true
+			}
+
+			continue
+		}
+
+		if strings.EqualFold(strings.Trim(expected, "[]"), hostname) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f fieldMatcher) match(values []string) bool {
+	if f.absent {
+		return len(values) == 0
+	}
+
+	if len(values) == 0 || !f.matchLength(values) {
+		return false
+	}
+
+	if f.regexp == nil {
+		return true
+	}
+
+	for _, value := range values {
+		if f.regexp.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f fieldMatcher) matchLength(values []string) bool {
+	if f.longerThan == 0 {
+		return true
+	}
+
+	for _, value := range values {
+		if len(value) > f.longerThan {
+			return true
+		}
+	}
+
+	return false
+}
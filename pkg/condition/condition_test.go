@@ -0,0 +1,461 @@
+package condition_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/condition"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		cond           *types.Condition
+		method         string
+		url            string
+		requestHeaders map[string]string
+		statusCode     int
+		contentType    string
+		want           bool
+	}{
+		{
+			name: "no condition",
+			want: true,
+		},
+		{
+			name: "empty condition",
+			cond: &types.Condition{},
+			want: true,
+		},
+		{
+			name: "method match",
+			cond: &types.Condition{Methods: []string{"post", "get"}},
+			want: true,
+		},
+		{
+			name:   "method mismatch",
+			cond:   &types.Condition{Methods: []string{"POST"}},
+			method: http.MethodDelete,
+			want:   false,
+		},
+		{
+			name: "host match ignoring port",
+			cond: &types.Condition{Hosts: []string{"Example.com"}},
+			url:  "http://example.com:8080/foo",
+			want: true,
+		},
+		{
+			name: "host mismatch",
+			cond: &types.Condition{Hosts: []string{"example.org"}},
+			want: false,
+		},
+		{
+			name: "path prefix match",
+			cond: &types.Condition{PathPrefix: "/foo"},
+			want: true,
+		},
+		{
+			name: "path prefix mismatch",
+			cond: &types.Condition{PathPrefix: "/bar"},
+			want: false,
+		},
+		{
+			name: "path regexp match",
+			cond: &types.Condition{PathRegexp: `^/api/v\d+/`},
+			url:  "http://example.com/api/v2/users",
+			want: true,
+		},
+		{
+			name: "path regexp mismatch",
+			cond: &types.Condition{PathRegexp: `^/api/v\d+/`},
+			want: false,
+		},
+		{
+			name: "query present",
+			cond: &types.Condition{Query: []types.FieldCondition{{Name: "tenant"}}},
+			url:  "http://example.com/foo?tenant=acme",
+			want: true,
+		},
+		{
+			name: "query value mismatch",
+			cond: &types.Condition{Query: []types.FieldCondition{{Name: "tenant", Regexp: "^globex$"}}},
+			url:  "http://example.com/foo?tenant=acme",
+			want: false,
+		},
+		{
+			name: "query absent",
+			cond: &types.Condition{Query: []types.FieldCondition{{Name: "tenant", Absent: true}}},
+			want: true,
+		},
+		{
+			name:           "header present",
+			cond:           &types.Condition{Headers: []types.FieldCondition{{Name: "X-Test"}}},
+			requestHeaders: map[string]string{"X-Test": "foo"},
+			want:           true,
+		},
+		{
+			name: "header missing",
+			cond: &types.Condition{Headers: []types.FieldCondition{{Name: "X-Test"}}},
+			want: false,
+		},
+		{
+			name:           "header absent mismatch",
+			cond:           &types.Condition{Headers: []types.FieldCondition{{Name: "X-Test", Absent: true}}},
+			requestHeaders: map[string]string{"X-Test": "foo"},
+			want:           false,
+		},
+		{
+			name:           "header value match",
+			cond:           &types.Condition{Headers: []types.FieldCondition{{Name: "X-Test", Regexp: "^f"}}},
+			requestHeaders: map[string]string{"X-Test": "foo"},
+			want:           true,
+		},
+		{
+			name: "Host header value match",
+			cond: &types.Condition{Headers: []types.FieldCondition{{Name: "Host", Regexp: `\.com$`}}},
+			want: true,
+		},
+		{
+			name: "and",
+			cond: &types.Condition{And: []types.Condition{
+				{Methods: []string{http.MethodGet}},
+				{PathPrefix: "/bar"},
+			}},
+			want: false,
+		},
+		{
+			name: "or",
+			cond: &types.Condition{Or: []types.Condition{
+				{Methods: []string{http.MethodPost}},
+				{PathPrefix: "/foo"},
+			}},
+			want: true,
+		},
+		{
+			name: "or none",
+			cond: &types.Condition{Or: []types.Condition{
+				{Methods: []string{http.MethodPost}},
+				{PathPrefix: "/bar"},
+			}},
+			want: false,
+		},
+		{
+			name: "not",
+			cond: &types.Condition{Not: &types.Condition{PathPrefix: "/foo"}},
+			want: false,
+		},
+		{
+			name: "fields and combinations",
+			cond: &types.Condition{
+				Methods: []string{http.MethodGet},
+				Or: []types.Condition{
+					{Headers: []types.FieldCondition{{Name: "X-Test"}}},
+					{Not: &types.Condition{PathPrefix: "/foo"}},
+				},
+			},
+			requestHeaders: map[string]string{"X-Test": "foo"},
+			want:           true,
+		},
+		{
+			name:       "status code match",
+			cond:       &types.Condition{StatusCodes: []string{"404", "503"}},
+			statusCode: http.StatusServiceUnavailable,
+			want:       true,
+		},
+		{
+			name:       "status code mismatch",
+			cond:       &types.Condition{StatusCodes: []string{"503"}},
+			statusCode: http.StatusOK,
+			want:       false,
+		},
+		{
+			name:       "status code class match",
+			cond:       &types.Condition{StatusCodes: []string{"4xx", "5XX"}},
+			statusCode: http.StatusNotFound,
+			want:       true,
+		},
+		{
+			name:       "status code range mismatch",
+			cond:       &types.Condition{StatusCodes: []string{"500-599"}},
+			statusCode: http.StatusNotFound,
+			want:       false,
+		},
+		{
+			name:        "content type match",
+			cond:        &types.Condition{ContentTypes: []string{"application/json"}},
+			contentType: "Application/JSON; charset=utf-8",
+			want:        true,
+		},
+		{
+			name:        "content type wildcard match",
+			cond:        &types.Condition{ContentTypes: []string{"text/*"}},
+			contentType: "text/html",
+			want:        true,
+		},
+		{
+			name:        "content type mismatch",
+			cond:        &types.Condition{ContentTypes: []string{"text/*"}},
+			contentType: "application/json",
+			want:        false,
+		},
+		{
+			name: "content type missing",
+			cond: &types.Condition{ContentTypes: []string{"*/*"}},
+			want: false,
+		},
+		{
+			name: "status code and content type",
+			cond: &types.Condition{Not: &types.Condition{
+				StatusCodes:  []string{"2xx"},
+				ContentTypes: []string{"application/json"},
+			}},
+			statusCode:  http.StatusBadRequest,
+			contentType: "application/json",
+			want:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			url := test.url
+			if url == "" {
+				url = "http://example.com/foo"
+			}
+
+			req := httptest.NewRequest(method, url, nil)
+			for hName, hVal := range test.requestHeaders {
+				req.Header.Add(hName, hVal)
+			}
+
+			req = req.WithContext(state.NewContext(req.Context(), &state.State{StatusCode: test.statusCode}))
+
+			rw := httptest.NewRecorder()
+			if test.contentType != "" {
+				rw.Header().Set("Content-Type", test.contentType)
+			}
+
+			matcher, err := condition.New(test.cond, true)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.want, matcher.Match(rw, req))
+		})
+	}
+}
+
+func TestMatchLongerThan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		longerThan int
+		values     []string
+		want       bool
+	}{
+		{
+			name:       "longer",
+			longerThan: 2,
+			values:     []string{"foo"},
+			want:       true,
+		},
+		{
+			name:       "not longer",
+			longerThan: 3,
+			values:     []string{"foo"},
+			want:       false,
+		},
+		{
+			name:       "one of the values longer",
+			longerThan: 3,
+			values:     []string{"foo", "foobar"},
+			want:       true,
+		},
+		{
+			name:       "missing",
+			longerThan: 3,
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := condition.New(&types.Condition{
+				Headers: []types.FieldCondition{{Name: "X-Test", LongerThan: test.longerThan}},
+			}, false)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Header["X-Test"] = test.values
+
+			assert.Equal(t, test.want, matcher.Match(httptest.NewRecorder(), req))
+		})
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		hosts []string
+		host  string
+		want  bool
+	}{
+		{
+			name:  "match with port",
+			hosts: []string{"example.com:8080"},
+			host:  "example.com:8080",
+			want:  true,
+		},
+		{
+			name:  "port mismatch",
+			hosts: []string{"example.com:8443"},
+			host:  "example.com:8080",
+			want:  false,
+		},
+		{
+			name:  "port missing from the request",
+			hosts: []string{"example.com:8080"},
+			host:  "example.com",
+			want:  false,
+		},
+		{
+			name:  "IPv6 match ignoring port",
+			hosts: []string{"[2001:db8::1]"},
+			host:  "[2001:db8::1]:8080",
+			want:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := condition.New(&types.Condition{Hosts: test.hosts}, false)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+test.host+"/foo", nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.want, matcher.Match(httptest.NewRecorder(), req))
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		cond       *types.Condition
+		onResponse bool
+		wantErr    bool
+	}{
+		{
+			name:    "no condition",
+			wantErr: false,
+		},
+		{
+			name:    "invalid path regexp",
+			cond:    &types.Condition{PathRegexp: "("},
+			wantErr: true,
+		},
+		{
+			name:    "field without name",
+			cond:    &types.Condition{Headers: []types.FieldCondition{{Regexp: "foo"}}},
+			wantErr: true,
+		},
+		{
+			name:    "field both absent and matching",
+			cond:    &types.Condition{Query: []types.FieldCondition{{Name: "foo", Absent: true, Regexp: "foo"}}},
+			wantErr: true,
+		},
+		{
+			name:    "field both absent and longer than",
+			cond:    &types.Condition{Headers: []types.FieldCondition{{Name: "foo", Absent: true, LongerThan: 8}}},
+			wantErr: true,
+		},
+		{
+			name:    "field longer than a negative length",
+			cond:    &types.Condition{Headers: []types.FieldCondition{{Name: "foo", LongerThan: -1}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid nested condition",
+			cond:    &types.Condition{Or: []types.Condition{{}, {Not: &types.Condition{PathRegexp: "("}}}},
+			wantErr: true,
+		},
+		{
+			name:    "status code on request",
+			cond:    &types.Condition{StatusCodes: []string{"200"}},
+			wantErr: true,
+		},
+		{
+			name:    "nested content type on request",
+			cond:    &types.Condition{Not: &types.Condition{ContentTypes: []string{"text/html"}}},
+			wantErr: true,
+		},
+		{
+			name:       "invalid status code",
+			cond:       &types.Condition{StatusCodes: []string{"2x"}},
+			onResponse: true,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid status range",
+			cond:       &types.Condition{StatusCodes: []string{"599-500"}},
+			onResponse: true,
+			wantErr:    true,
+		},
+		{
+			name: "valid response condition",
+			cond: &types.Condition{
+				StatusCodes:  []string{"200", "3xx", "500-599"},
+				ContentTypes: []string{"text/*"},
+			},
+			onResponse: true,
+			wantErr:    false,
+		},
+		{
+			name: "valid condition",
+			cond: &types.Condition{
+				Methods:    []string{http.MethodGet},
+				PathRegexp: "^/foo",
+				Headers:    []types.FieldCondition{{Name: "X-Test", Regexp: "foo"}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := condition.New(test.cond, test.onResponse)
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
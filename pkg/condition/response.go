@@ -0,0 +1,131 @@
+package condition
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+// statusClassSize is the number of status codes in a status class (e.g. 4xx).
+const statusClassSize = 100
+
+// statusRange is an inclusive range of status codes.
+type statusRange struct {
+	first, last int
+}
+
+// newStatusRanges parses status codes written either as a single code (503),
+// a class (4xx) or a range (500-599).
+func newStatusRanges(codes []string) ([]statusRange, error) {
+	ranges := make([]statusRange, 0, len(codes))
+
+	for _, code := range codes {
+		r, err := parseStatusRange(strings.ToLower(strings.TrimSpace(code)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid status code %q", types.ErrInvalidCondition, code)
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+func parseStatusRange(code string) (statusRange, error) {
+	if len(code) == 3 && strings.HasSuffix(code, "xx") {
+		class, err := strconv.Atoi(code[:1])
+		if err != nil {
+			return statusRange{}, fmt.Errorf("parse status class: %w", err)
+		}
+
+		return statusRange{first: class * statusClassSize, last: (class+1)*statusClassSize - 1}, nil
+	}
+
+	firstStr, lastStr, isRange := strings.Cut(code, "-")
+	if !isRange {
+		lastStr = firstStr
+	}
+
+	first, err := strconv.Atoi(firstStr)
+	if err != nil {
+		return statusRange{}, fmt.Errorf("parse status code: %w", err)
+	}
+
+	last, err := strconv.Atoi(lastStr)
+	if err != nil {
+		return statusRange{}, fmt.Errorf("parse status code: %w", err)
+	}
+
+	if first > last {
+		return statusRange{}, fmt.Errorf("%w: empty status range", types.ErrInvalidCondition)
+	}
+
+	return statusRange{first: first, last: last}, nil
+}
+
+func (r statusRange) contains(code int) bool {
+	return r.first <= code && code <= r.last
+}
+
+func newContentTypes(contentTypes []string) []string {
+	mediaTypes := make([]string, 0, len(contentTypes))
+	for _, contentType := range contentTypes {
+		mediaTypes = append(mediaTypes, mediaType(contentType))
+	}
+
+	return mediaTypes
+}
+
+// mediaType returns the lower-cased media type of a Content-Type value, without its parameters.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+
+	mt, _, _ := strings.Cut(contentType, ";")
+
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+func (m *Matcher) matchResponse(rw http.ResponseWriter, req *http.Request) bool {
+	if len(m.statusCodes) > 0 && !m.matchStatusCode(state.FromRequest(req).StatusCode) {
+		return false
+	}
+
+	return len(m.contentTypes) == 0 || m.matchContentType(rw.Header().Get("Content-Type"))
+}
+
+func (m *Matcher) matchStatusCode(code int) bool {
+	for _, r := range m.statusCodes {
+		if r.contains(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Matcher) matchContentType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+
+	actual := mediaType(contentType)
+
+	for _, expected := range m.contentTypes {
+		if expected == "*/*" || expected == actual {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(expected, "/*"); ok && strings.HasPrefix(actual, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
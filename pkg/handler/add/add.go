@@ -0,0 +1,70 @@
+package add
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/template"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+type Add struct {
+	rule   *types.Rule
+	values []*template.Template
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	values, err := template.ParseAll(rule.Values)
+	if err != nil {
+		return nil, fmt.Errorf("values: %w", err)
+	}
+
+	return &Add{rule: &rule, values: values}, nil
+}
+
+func (a *Add) Validate() error {
+	if a.rule.Header == "" || len(a.rule.Values) == 0 {
+		return types.ErrMissingRequiredFields
+	}
+
+	if !a.rule.SetOnResponse && strings.EqualFold(a.rule.Header, "Host") {
+		return fmt.Errorf("%w: the Host has a single value, it cannot be added to", types.ErrInvalidOption)
+	}
+
+	return nil
+}
+
+func (a *Add) Handle(rw http.ResponseWriter, req *http.Request) {
+	var values []string
+	if a.rule.SetOnResponse {
+		values = rw.Header().Values(a.rule.Header)
+	} else {
+		values = header.Values(req, a.rule.Header)
+	}
+
+	// index the existing values as adding new ones may change the underlying slice.
+	existing := make(map[string]bool, len(values))
+	for _, value := range values {
+		existing[value] = true
+	}
+
+	for _, tmpl := range a.values {
+		value := header.Resolve(req, tmpl.Execute(req), a.rule.HeaderPrefix)
+
+		if a.rule.Deduplicate {
+			if existing[value] {
+				continue
+			}
+
+			existing[value] = true
+		}
+
+		if a.rule.SetOnResponse {
+			rw.Header().Add(a.rule.Header, value)
+		} else {
+			header.Add(req, a.rule.Header, value)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package add_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/add"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestAddHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		requestHeaders  map[string][]string
+		responseHeaders map[string][]string
+		wantOnRequest   map[string][]string
+		wantOnResponse  map[string][]string
+	}{
+		{
+			name: "add to missing header",
+			rule: types.Rule{
+				Header: "Via",
+				Values: []string{"1.1 traefik"},
+			},
+			wantOnRequest: map[string][]string{
+				"Via": {"1.1 traefik"},
+			},
+		},
+		{
+			name: "add several values to existing header",
+			rule: types.Rule{
+				Header: "Link",
+				Values: []string{"</style.css>; rel=preload", "</script.js>; rel=preload"},
+			},
+			requestHeaders: map[string][]string{
+				"Link": {"</>; rel=canonical"},
+			},
+			wantOnRequest: map[string][]string{
+				"Link": {"</>; rel=canonical", "</style.css>; rel=preload", "</script.js>; rel=preload"},
+			},
+		},
+		{
+			name: "add duplicated values",
+			rule: types.Rule{
+				Header: "Via",
+				Values: []string{"1.1 traefik", "1.1 traefik"},
+			},
+			requestHeaders: map[string][]string{
+				"Via": {"1.1 traefik"},
+			},
+			wantOnRequest: map[string][]string{
+				"Via": {"1.1 traefik", "1.1 traefik", "1.1 traefik"},
+			},
+		},
+		{
+			name: "add deduplicated values",
+			rule: types.Rule{
+				Header:      "Via",
+				Values:      []string{"1.1 traefik", "1.1 proxy", "1.1 proxy"},
+				Deduplicate: true,
+			},
+			requestHeaders: map[string][]string{
+				"Via": {"1.1 traefik"},
+			},
+			wantOnRequest: map[string][]string{
+				"Via": {"1.1 traefik", "1.1 proxy"},
+			},
+		},
+		{
+			name: "add template values",
+			rule: types.Rule{
+				Header: "Via",
+				Values: []string{`1.1 {{ .Host }}`},
+			},
+			wantOnRequest: map[string][]string{
+				"Via": {"1.1 example.com"},
+			},
+		},
+		{
+			name: "add on response",
+			rule: types.Rule{
+				Header:        "Set-Cookie",
+				Values:        []string{"foo=bar", "baz=qux"},
+				SetOnResponse: true,
+			},
+			requestHeaders: map[string][]string{
+				"Set-Cookie": {"request=true"},
+			},
+			responseHeaders: map[string][]string{
+				"Set-Cookie": {"session=1"},
+			},
+			wantOnRequest: map[string][]string{
+				"Set-Cookie": {"request=true"},
+			},
+			wantOnResponse: map[string][]string{
+				"Set-Cookie": {"session=1", "foo=bar", "baz=qux"},
+			},
+		},
+		{
+			name: "add deduplicated on response",
+			rule: types.Rule{
+				Header:        "Warning",
+				Values:        []string{`110 - "Response is Stale"`},
+				SetOnResponse: true,
+				Deduplicate:   true,
+			},
+			responseHeaders: map[string][]string{
+				"Warning": {`110 - "Response is Stale"`},
+			},
+			wantOnResponse: map[string][]string{
+				"Warning": {`110 - "Response is Stale"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVals := range test.requestHeaders {
+				for _, hVal := range hVals {
+					req.Header.Add(hName, hVal)
+				}
+			}
+
+			rw := httptest.NewRecorder()
+
+			for hName, hVals := range test.responseHeaders {
+				for _, hVal := range hVals {
+					rw.Header().Add(hName, hVal)
+				}
+			}
+
+			addHandler, err := add.New(test.rule)
+			require.NoError(t, err)
+
+			addHandler.Handle(rw, req)
+
+			for hName, hVals := range test.wantOnRequest {
+				assert.Equalf(t, hVals, req.Header.Values(hName), "request header %q", hName)
+			}
+
+			for hName, hVals := range test.wantOnResponse {
+				assert.Equalf(t, hVals, rw.Header().Values(hName), "response header %q", hName)
+			}
+
+			assert.Equal(t, "example.com", req.Host)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: true,
+		},
+		{
+			name: "missing header",
+			rule: types.Rule{
+				Values: []string{"not-empty"},
+				Type:   types.Add,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing values",
+			rule: types.Rule{
+				Header: "not-empty",
+				Type:   types.Add,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Host",
+			rule: types.Rule{
+				Header: "host",
+				Values: []string{"example.org"},
+				Type:   types.Add,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "not-empty",
+				Values: []string{"not-empty"},
+				Type:   types.Add,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			addHandler, err := add.New(test.rule)
+			require.NoError(t, err)
+
+			err = addHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/forwarded"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+// forwardingHeaders are the headers a client can use to spoof its address.
+func forwardingHeaders() []string {
+	return []string{"Forwarded", "X-Forwarded-For", "X-Forwarded-Proto", "X-Forwarded-Host", "X-Real-Ip"}
+}
+
+// defaultSource is the header read by default, as written by Traefik and most load balancers.
+const defaultSource = "X-Forwarded-For"
+
+// ClientIP sets a header to the address of the client, as forwarded by the trusted proxies.
+type ClientIP struct {
+	rule    *types.Rule
+	trusted []*net.IPNet
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Source == "" {
+		rule.Source = defaultSource
+	}
+
+	trusted := make([]*net.IPNet, 0, len(rule.TrustedProxies))
+
+	for _, proxy := range rule.TrustedProxies {
+		cidr := proxy
+		if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else if ip != nil {
+			cidr += "/128"
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: TrustedProxies %q", types.ErrInvalidOption, proxy)
+		}
+
+		trusted = append(trusted, network)
+	}
+
+	return &ClientIP{rule: &rule, trusted: trusted}, nil
+}
+
+func (c *ClientIP) Validate() error {
+	if c.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	if c.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (c *ClientIP) Handle(_ http.ResponseWriter, req *http.Request) {
+	peer := net.ParseIP(forwarded.Address(req.RemoteAddr))
+	if peer == nil {
+		return
+	}
+
+	if !c.isTrusted(peer) {
+		if c.rule.StripUntrusted {
+			for _, name := range forwardingHeaders() {
+				req.Header.Del(name)
+			}
+
+			req.Header.Del(c.rule.Source)
+		}
+
+		header.Set(req, c.rule.Header, peer.String())
+
+		return
+	}
+
+	client := c.forwardedClient(req, peer)
+	if client == nil {
+		header.Delete(req, c.rule.Header)
+
+		return
+	}
+
+	header.Set(req, c.rule.Header, client.String())
+}
+
+// forwardedClient returns the client address forwarded by the trusted peer, or nil if it is unknown.
+func (c *ClientIP) forwardedClient(req *http.Request, peer net.IP) net.IP {
+	chain, valid := c.chain(req)
+	if !valid {
+		return nil
+	}
+
+	client := peer
+
+	// the addresses are appended by each proxy, the client being the first one not trusted from the right.
+	for i := len(chain) - 1; i >= 0 && c.isTrusted(client); i-- {
+		// a value that is not an address, such as unknown, hides the client behind it.
+		client = net.ParseIP(forwarded.Address(chain[i]))
+		if client == nil {
+			return nil
+		}
+	}
+
+	return client
+}
+
+// chain returns the forwarded addresses from the Source header, it returns false if the header is invalid.
+// The other forwarding headers are ignored, as the trusted proxies do not overwrite them.
+func (c *ClientIP) chain(req *http.Request) ([]string, bool) {
+	values := req.Header.Values(c.rule.Source)
+
+	if http.CanonicalHeaderKey(c.rule.Source) == "Forwarded" {
+		elements, valid := forwarded.Parse(values)
+		if !valid {
+			return nil, false
+		}
+
+		chain := make([]string, 0, len(elements))
+		for _, element := range elements {
+			chain = append(chain, element.For)
+		}
+
+		return chain, true
+	}
+
+	var chain []string
+
+	for _, value := range values {
+		for _, address := range strings.Split(value, ",") {
+			chain = append(chain, strings.TrimSpace(address))
+		}
+	}
+
+	return chain, true
+}
+
+func (c *ClientIP) isTrusted(ip net.IP) bool {
+	for _, network := range c.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
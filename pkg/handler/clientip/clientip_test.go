@@ -0,0 +1,337 @@
+package clientip_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/clientip"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestClientIPHandler(t *testing.T) {
+	t.Parallel()
+
+	trusted := []string{"10.0.0.0/8", "2001:db8::1"}
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		remoteAddr  string
+		headers     http.Header
+		wantHeaders http.Header
+	}{
+		{
+			name: "untrusted peer",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "192.0.2.43:47011",
+			headers: http.Header{
+				"X-Forwarded-For": {"198.51.100.17"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"198.51.100.17"},
+				"X-Client-Ip":     {"192.0.2.43"},
+			},
+		},
+		{
+			name: "untrusted peer stripping spoofed headers",
+			rule: types.Rule{
+				Header:         "X-Real-Ip",
+				TrustedProxies: trusted,
+				StripUntrusted: true,
+			},
+			remoteAddr: "192.0.2.43:47011",
+			headers: http.Header{
+				"Forwarded":         {"for=198.51.100.17"},
+				"X-Forwarded-For":   {"198.51.100.17"},
+				"X-Forwarded-Proto": {"https"},
+				"X-Real-Ip":         {"198.51.100.17"},
+				"Accept":            {"*/*"},
+			},
+			wantHeaders: http.Header{
+				"Accept":    {"*/*"},
+				"X-Real-Ip": {"192.0.2.43"},
+			},
+		},
+		{
+			name: "X-Forwarded-For through trusted proxies",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+				StripUntrusted: true,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"203.0.113.5, 198.51.100.17", "10.0.0.1"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"203.0.113.5, 198.51.100.17", "10.0.0.1"},
+				"X-Client-Ip":     {"198.51.100.17"},
+			},
+		},
+		{
+			name: "Forwarded through a trusted IPv6 proxy",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "Forwarded",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "[2001:db8::1]:4711",
+			headers: http.Header{
+				"Forwarded":       {`for="[2001:db8:cafe::17]:4711", for=10.0.0.1`},
+				"X-Forwarded-For": {"198.51.100.17"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded":       {`for="[2001:db8:cafe::17]:4711", for=10.0.0.1`},
+				"X-Forwarded-For": {"198.51.100.17"},
+				"X-Client-Ip":     {"2001:db8:cafe::17"},
+			},
+		},
+		{
+			name: "X-Real-IP from a trusted proxy",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "X-Real-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Real-Ip": {"198.51.100.17"},
+			},
+			wantHeaders: http.Header{
+				"X-Real-Ip":   {"198.51.100.17"},
+				"X-Client-Ip": {"198.51.100.17"},
+			},
+		},
+		{
+			name: "Forwarded sent by the client behind a trusted proxy",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.1:1234",
+			headers: http.Header{
+				"Forwarded":       {"for=198.51.100.66"},
+				"X-Forwarded-For": {"203.0.113.7"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded":       {"for=198.51.100.66"},
+				"X-Forwarded-For": {"203.0.113.7"},
+				"X-Client-Ip":     {"203.0.113.7"},
+			},
+		},
+		{
+			name: "no fallback on the headers not written by the proxies",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "Forwarded",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.1:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"198.51.100.66"},
+				"X-Real-Ip":       {"198.51.100.66"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"198.51.100.66"},
+				"X-Real-Ip":       {"198.51.100.66"},
+				"X-Client-Ip":     {"10.0.0.1"},
+			},
+		},
+		{
+			name: "untrusted peer stripping a custom source",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "Cf-Connecting-Ip",
+				TrustedProxies: trusted,
+				StripUntrusted: true,
+			},
+			remoteAddr: "192.0.2.43:47011",
+			headers: http.Header{
+				"Cf-Connecting-Ip": {"198.51.100.66"},
+			},
+			wantHeaders: http.Header{
+				"X-Client-Ip": {"192.0.2.43"},
+			},
+		},
+		{
+			name: "every address trusted",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"10.0.0.3, 10.0.0.1"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"10.0.0.3, 10.0.0.1"},
+				"X-Client-Ip":     {"10.0.0.3"},
+			},
+		},
+		{
+			name: "unknown address",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "forwarded",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"Forwarded":   {"for=unknown, for=10.0.0.1"},
+				"X-Client-Ip": {"198.51.100.66"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded": {"for=unknown, for=10.0.0.1"},
+			},
+		},
+		{
+			name: "invalid X-Forwarded-For address",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"203.0.113.5, not-an-ip, 10.0.0.1"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"203.0.113.5, not-an-ip, 10.0.0.1"},
+			},
+		},
+		{
+			name: "invalid Forwarded header",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				Source:         "Forwarded",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"Forwarded": {`for="198.51.100.66`},
+			},
+			wantHeaders: http.Header{
+				"Forwarded": {`for="198.51.100.66`},
+			},
+		},
+		{
+			name: "invalid address left of the client",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: trusted,
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"not-an-ip, 203.0.113.5, 10.0.0.1"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"not-an-ip, 203.0.113.5, 10.0.0.1"},
+				"X-Client-Ip":     {"203.0.113.5"},
+			},
+		},
+		{
+			name: "no trusted proxy",
+			rule: types.Rule{
+				Header: "X-Client-Ip",
+			},
+			remoteAddr: "10.0.0.2:1234",
+			headers: http.Header{
+				"X-Forwarded-For": {"198.51.100.17"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For": {"198.51.100.17"},
+				"X-Client-Ip":     {"10.0.0.2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.RemoteAddr = test.remoteAddr
+			req.Header = test.headers
+
+			clientIPHandler, err := clientip.New(test.rule)
+			require.NoError(t, err)
+
+			clientIPHandler.Handle(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.wantHeaders, req.Header)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid trusted proxy",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: []string{"10.0.0.0/33"},
+				Type:           types.ClientIP,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Client-Ip",
+				Type:          types.ClientIP,
+				SetOnResponse: true,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:         "X-Client-Ip",
+				TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"},
+				Type:           types.ClientIP,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			clientIPHandler, err := clientip.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = clientIPHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
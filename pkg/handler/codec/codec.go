@@ -0,0 +1,162 @@
+package codec
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/encoding"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+)
+
+// Codec encodes, or decodes, the values of a header in place, or into the Value header.
+type Codec struct {
+	rule   *types.Rule
+	decode bool
+}
+
+// NewEncode returns a handler encoding the values of a header.
+func NewEncode(rule types.Rule) (types.Handler, error) {
+	return newCodec(rule, false), nil
+}
+
+// NewDecode returns a handler decoding the values of a header, applying the failure policy if one of them cannot be
+// decoded.
+func NewDecode(rule types.Rule) (types.Handler, error) {
+	if rule.OnFailure == "" {
+		rule.OnFailure = types.FailureLeave
+	}
+
+	if rule.StatusCode == 0 {
+		rule.StatusCode = http.StatusBadRequest
+	}
+
+	return newCodec(rule, true), nil
+}
+
+func newCodec(rule types.Rule, decode bool) *Codec {
+	if rule.Value == "" {
+		rule.Value = rule.Header
+	}
+
+	return &Codec{rule: &rule, decode: decode}
+}
+
+func (c *Codec) Validate() error {
+	if c.rule.Header == "" || c.rule.Encoding == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	if !encoding.Supported(c.rule.Encoding) {
+		return fmt.Errorf("%w: Encoding %q", types.ErrInvalidOption, c.rule.Encoding)
+	}
+
+	if !c.decode {
+		return nil
+	}
+
+	return c.validateFailure()
+}
+
+func (c *Codec) validateFailure() error {
+	switch c.rule.OnFailure {
+	case types.FailureLeave, types.FailureDelete:
+	case types.FailureReject:
+		if c.rule.SetOnResponse {
+			return fmt.Errorf("%w: a response cannot be rejected", types.ErrInvalidOption)
+		}
+	case types.FailureFlag:
+		return fmt.Errorf("%w: OnFailure %q is not supported by Decode", types.ErrInvalidOption, c.rule.OnFailure)
+	default:
+		return fmt.Errorf("%w: OnFailure %q", types.ErrInvalidOption, c.rule.OnFailure)
+	}
+
+	if c.rule.StatusCode < http.StatusOK || c.rule.StatusCode > 599 {
+		return fmt.Errorf("%w: StatusCode %d", types.ErrInvalidOption, c.rule.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Codec) Handle(rw http.ResponseWriter, req *http.Request) {
+	var values []string
+	if c.rule.SetOnResponse {
+		values = rw.Header().Values(c.rule.Header)
+	} else {
+		values = header.Values(req, c.rule.Header)
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	converted, ok := c.convert(values)
+	if !ok {
+		c.fail(rw, req)
+
+		return
+	}
+
+	if c.rule.SetOnResponse {
+		rw.Header().Del(c.rule.Value)
+
+		for _, value := range converted {
+			rw.Header().Add(c.rule.Value, value)
+		}
+
+		return
+	}
+
+	header.Replace(req, c.rule.Value, converted)
+}
+
+// convert returns the encoded, or decoded, values, it returns false if one of them cannot be decoded into a valid
+// header value.
+func (c *Codec) convert(values []string) ([]string, bool) {
+	converted := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if !c.decode {
+			converted = append(converted, encoding.Encode(c.rule.Encoding, []byte(value)))
+
+			continue
+		}
+
+		decoded, err := encoding.Decode(c.rule.Encoding, value)
+		if err != nil || !validValue(decoded) {
+			return nil, false
+		}
+
+		converted = append(converted, string(decoded))
+	}
+
+	return converted, true
+}
+
+// fail applies the failure policy to a header that cannot be decoded.
+func (c *Codec) fail(rw http.ResponseWriter, req *http.Request) {
+	switch c.rule.OnFailure {
+	case types.FailureDelete:
+		if c.rule.SetOnResponse {
+			rw.Header().Del(c.rule.Header)
+		} else {
+			header.Delete(req, c.rule.Header)
+		}
+	case types.FailureReject:
+		response.Write(rw, c.rule.StatusCode, c.rule.Body)
+	case types.FailureLeave, types.FailureFlag:
+	}
+}
+
+// validValue reports whether the decoded value can be sent in a header: it must not hold control characters other
+// than horizontal tabs.
+func validValue(value []byte) bool {
+	for _, char := range value {
+		if (char < ' ' && char != '\t') || char == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
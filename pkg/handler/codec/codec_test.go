@@ -0,0 +1,286 @@
+package codec_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/codec"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestCodecHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		newHandler  func(types.Rule) (types.Handler, error)
+		headers     http.Header
+		wantHeaders http.Header
+		wantStatus  int
+	}{
+		{
+			name:        "encode in place",
+			rule:        types.Rule{Header: "X-User-Info", Encoding: types.EncodingBase64},
+			newHandler:  codec.NewEncode,
+			headers:     http.Header{"X-User-Info": {`{"id":1}`}},
+			wantHeaders: http.Header{"X-User-Info": {"eyJpZCI6MX0="}},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:       "encode into another header",
+			rule:       types.Rule{Header: "X-Path", Value: "X-Escaped-Path", Encoding: types.EncodingURLPath},
+			newHandler: codec.NewEncode,
+			headers:    http.Header{"X-Path": {"a b/c"}},
+			wantHeaders: http.Header{
+				"X-Path":         {"a b/c"},
+				"X-Escaped-Path": {"a%20b%2Fc"},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "decode every value",
+			rule:        types.Rule{Header: "X-Query", Encoding: types.EncodingURLQuery},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-Query": {"a+b", "c%26d"}},
+			wantHeaders: http.Header{"X-Query": {"a b", "c&d"}},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "decode MIME encoded-word",
+			rule:        types.Rule{Header: "X-Name", Encoding: types.EncodingMIMEWord},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-Name": {"=?utf-8?q?Caf=C3=A9?="}},
+			wantHeaders: http.Header{"X-Name": {"Café"}},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "decode failure left unchanged",
+			rule:        types.Rule{Header: "X-User-Info", Value: "X-Decoded", Encoding: types.EncodingBase64},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-User-Info": {"not base64!"}},
+			wantHeaders: http.Header{"X-User-Info": {"not base64!"}},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name: "decode failure deleted",
+			rule: types.Rule{
+				Header:    "X-User-Info",
+				Encoding:  types.EncodingBase64,
+				OnFailure: types.FailureDelete,
+			},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-User-Info": {"eyJpZCI6MX0=", "not base64!"}},
+			wantHeaders: http.Header{},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name: "decode failure rejected",
+			rule: types.Rule{
+				Header:     "X-User-Info",
+				Encoding:   types.EncodingBase64,
+				OnFailure:  types.FailureReject,
+				StatusCode: http.StatusUnprocessableEntity,
+			},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-User-Info": {"not base64!"}},
+			wantHeaders: http.Header{"X-User-Info": {"not base64!"}},
+			wantStatus:  http.StatusUnprocessableEntity,
+		},
+		{
+			name: "decoded control characters",
+			rule: types.Rule{
+				Header:    "X-User-Info",
+				Encoding:  types.EncodingHex,
+				OnFailure: types.FailureReject,
+			},
+			newHandler:  codec.NewDecode,
+			headers:     http.Header{"X-User-Info": {"610d0a62"}},
+			wantHeaders: http.Header{"X-User-Info": {"610d0a62"}},
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Header = test.headers
+
+			codecHandler, err := test.newHandler(test.rule)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			codecHandler.Handle(rw, req)
+
+			assert.Equal(t, test.wantHeaders, req.Header)
+			assert.Equal(t, test.wantStatus, rw.Code)
+		})
+	}
+}
+
+func TestCodecResponse(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+	require.NoError(t, err)
+
+	codecHandler, err := codec.NewDecode(types.Rule{
+		Header:        "X-Info",
+		Value:         "X-Decoded-Info",
+		Encoding:      types.EncodingBase64URL,
+		SetOnResponse: true,
+	})
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	rw.Header().Set("X-Info", "-_8-Zg")
+
+	codecHandler.Handle(rw, req)
+
+	assert.Equal(t, "\xfb\xff\x3ef", rw.Header().Get("X-Decoded-Info"))
+}
+
+func TestCodecHost(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+	require.NoError(t, err)
+
+	req.Header["X-Host"] = []string{"ZXhhbXBsZS5vcmc=", "ZXhhbXBsZS5uZXQ="}
+
+	codecHandler, err := codec.NewDecode(types.Rule{
+		Header:   "X-Host",
+		Value:    "Host",
+		Encoding: types.EncodingBase64,
+	})
+	require.NoError(t, err)
+
+	codecHandler.Handle(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "example.org", req.Host)
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		rule       types.Rule
+		newHandler func(types.Rule) (types.Handler, error)
+		wantErr    bool
+	}{
+		{
+			name:       "no rules",
+			newHandler: codec.NewEncode,
+			wantErr:    true,
+		},
+		{
+			name:       "missing encoding",
+			rule:       types.Rule{Header: "X-User-Info", Type: types.Decode},
+			newHandler: codec.NewDecode,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid encoding",
+			rule:       types.Rule{Header: "X-User-Info", Encoding: "Base32", Type: types.Encode},
+			newHandler: codec.NewEncode,
+			wantErr:    true,
+		},
+		{
+			name: "invalid failure policy",
+			rule: types.Rule{
+				Header:    "X-User-Info",
+				Encoding:  types.EncodingBase64,
+				OnFailure: types.FailureFlag,
+				Type:      types.Decode,
+			},
+			newHandler: codec.NewDecode,
+			wantErr:    true,
+		},
+		{
+			name: "reject on response",
+			rule: types.Rule{
+				Header:        "X-User-Info",
+				Encoding:      types.EncodingBase64,
+				OnFailure:     types.FailureReject,
+				Type:          types.Decode,
+				SetOnResponse: true,
+			},
+			newHandler: codec.NewDecode,
+			wantErr:    true,
+		},
+		{
+			name: "invalid status code",
+			rule: types.Rule{
+				Header:     "X-User-Info",
+				Encoding:   types.EncodingBase64,
+				OnFailure:  types.FailureReject,
+				StatusCode: 99,
+				Type:       types.Decode,
+			},
+			newHandler: codec.NewDecode,
+			wantErr:    true,
+		},
+		{
+			name: "informational status code",
+			rule: types.Rule{
+				Header:     "X-User-Info",
+				Encoding:   types.EncodingBase64,
+				OnFailure:  types.FailureReject,
+				StatusCode: http.StatusSwitchingProtocols,
+				Type:       types.Decode,
+			},
+			newHandler: codec.NewDecode,
+			wantErr:    true,
+		},
+		{
+			name: "valid encode rule on response",
+			rule: types.Rule{
+				Header:        "X-User-Info",
+				Encoding:      types.EncodingMIMEWord,
+				Type:          types.Encode,
+				SetOnResponse: true,
+			},
+			newHandler: codec.NewEncode,
+			wantErr:    false,
+		},
+		{
+			name: "valid decode rule",
+			rule: types.Rule{
+				Header:    "X-User-Info",
+				Value:     "X-Decoded-User-Info",
+				Encoding:  types.EncodingBase64,
+				OnFailure: types.FailureReject,
+				Type:      types.Decode,
+			},
+			newHandler: codec.NewDecode,
+			wantErr:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			codecHandler, err := test.newHandler(test.rule)
+			require.NoError(t, err)
+
+			err = codecHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
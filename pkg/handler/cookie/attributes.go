@@ -0,0 +1,127 @@
+package cookie
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// Attributes forces attributes on the cookies set by the response.
+type Attributes struct {
+	rule *types.Rule
+}
+
+// sameSiteModes returns the SameSite attribute values.
+func sameSiteModes() map[string]http.SameSite {
+	return map[string]http.SameSite{
+		"Lax":    http.SameSiteLaxMode,
+		"Strict": http.SameSiteStrictMode,
+		"None":   http.SameSiteNoneMode,
+	}
+}
+
+func NewAttributes(rule types.Rule) (types.Handler, error) {
+	if rule.Cookie != "" {
+		re, err := nameRegexp(rule.Cookie)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+		}
+
+		rule.Regexp = re
+	}
+
+	return &Attributes{rule: &rule}, nil
+}
+
+func (a *Attributes) Validate() error {
+	if a.rule.Attributes == nil {
+		return types.ErrMissingRequiredFields
+	}
+
+	if !a.rule.SetOnResponse {
+		return types.ErrResponseOnly
+	}
+
+	if _, ok := sameSiteModes()[a.rule.Attributes.SameSite]; !ok && a.rule.Attributes.SameSite != "" {
+		return fmt.Errorf("%w: SameSite %q", types.ErrInvalidOption, a.rule.Attributes.SameSite)
+	}
+
+	return nil
+}
+
+func (a *Attributes) Handle(rw http.ResponseWriter, _ *http.Request) {
+	values := rw.Header().Values("Set-Cookie")
+	if len(values) == 0 {
+		return
+	}
+
+	updated := make([]string, 0, len(values))
+
+	for _, value := range values {
+		cookie := readSetCookie(value)
+		if cookie == nil || (a.rule.Regexp != nil && !a.rule.Regexp.MatchString(cookie.Name)) {
+			updated = append(updated, value)
+
+			continue
+		}
+
+		a.apply(cookie)
+
+		updated = append(updated, writeSetCookie(cookie))
+	}
+
+	rw.Header().Del("Set-Cookie")
+
+	for _, value := range updated {
+		rw.Header().Add("Set-Cookie", value)
+	}
+}
+
+// apply forces the rule attributes on the cookie.
+func (a *Attributes) apply(cookie *http.Cookie) {
+	attributes := a.rule.Attributes
+
+	cookie.Secure = cookie.Secure || attributes.Secure
+	cookie.HttpOnly = cookie.HttpOnly || attributes.HTTPOnly
+
+	if sameSite, ok := sameSiteModes()[attributes.SameSite]; ok {
+		cookie.SameSite = sameSite
+	}
+
+	if attributes.Domain != "" {
+		cookie.Domain = attributes.Domain
+	}
+
+	if attributes.Path != "" {
+		cookie.Path = attributes.Path
+	}
+
+	if attributes.MaxAge != 0 {
+		cookie.MaxAge = attributes.MaxAge
+	}
+}
+
+// readSetCookie parses a Set-Cookie header value, it returns nil if the value is invalid.
+// The response parser is used as http.ParseSetCookie is not available in yaegi.
+func readSetCookie(value string) *http.Cookie {
+	resp := new(http.Response)
+	resp.Header = http.Header{"Set-Cookie": {value}}
+
+	cookies := resp.Cookies()
+	if len(cookies) != 1 {
+		return nil
+	}
+
+	return cookies[0]
+}
+
+// writeSetCookie serializes the cookie, keeping the attributes unknown to net/http.
+func writeSetCookie(cookie *http.Cookie) string {
+	if len(cookie.Unparsed) == 0 {
+		return cookie.String()
+	}
+
+	return cookie.String() + "; " + strings.Join(cookie.Unparsed, "; ")
+}
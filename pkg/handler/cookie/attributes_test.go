@@ -0,0 +1,121 @@
+package cookie_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/cookie"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestAttributesHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rule       types.Rule
+		setCookies []string
+		want       []string
+	}{
+		{
+			name: "force attributes on every cookie",
+			rule: types.Rule{
+				Attributes: &types.SetCookieAttributes{
+					Secure:   true,
+					HTTPOnly: true,
+					SameSite: "Strict",
+				},
+			},
+			setCookies: []string{"session=1234; Path=/", "theme=dark"},
+			want: []string{
+				"session=1234; Path=/; HttpOnly; Secure; SameSite=Strict",
+				"theme=dark; HttpOnly; Secure; SameSite=Strict",
+			},
+		},
+		{
+			name: "force attributes on matching cookies",
+			rule: types.Rule{
+				Cookie: "session|auth_.*",
+				Attributes: &types.SetCookieAttributes{
+					Domain: "example.com",
+					Path:   "/app",
+					MaxAge: 3600,
+				},
+			},
+			setCookies: []string{"session=1234; Path=/", "theme=dark", "auth_token=abcd", "session_id=5678"},
+			want: []string{
+				"session=1234; Path=/app; Domain=example.com; Max-Age=3600",
+				"theme=dark",
+				"auth_token=abcd; Path=/app; Domain=example.com; Max-Age=3600",
+				"session_id=5678",
+			},
+		},
+		{
+			name: "expire cookies",
+			rule: types.Rule{
+				Cookie: "session",
+				Attributes: &types.SetCookieAttributes{
+					MaxAge: -1,
+				},
+			},
+			setCookies: []string{"session=1234; Max-Age=3600"},
+			want:       []string{"session=1234; Max-Age=0"},
+		},
+		{
+			name: "keep unknown attributes",
+			rule: types.Rule{
+				Attributes: &types.SetCookieAttributes{
+					Secure: true,
+				},
+			},
+			setCookies: []string{"session=1234; Priority=High"},
+			want:       []string{"session=1234; Secure; Priority=High"},
+		},
+		{
+			name: "keep invalid cookies",
+			rule: types.Rule{
+				Attributes: &types.SetCookieAttributes{
+					Secure: true,
+				},
+			},
+			setCookies: []string{"invalid"},
+			want:       []string{"invalid"},
+		},
+		{
+			name: "no cookies",
+			rule: types.Rule{
+				Attributes: &types.SetCookieAttributes{
+					Secure: true,
+				},
+			},
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			for _, setCookie := range test.setCookies {
+				rw.Header().Add("Set-Cookie", setCookie)
+			}
+
+			test.rule.SetOnResponse = true
+
+			attributesHandler, err := cookie.NewAttributes(test.rule)
+			require.NoError(t, err)
+
+			attributesHandler.Handle(rw, req)
+
+			assert.Equal(t, test.want, rw.Header().Values("Set-Cookie"))
+		})
+	}
+}
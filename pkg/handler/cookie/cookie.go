@@ -0,0 +1,183 @@
+package cookie
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/tomMoulard/htransformation/pkg/template"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+// action defines what a Cookie rule does to the request cookies.
+type action int
+
+const (
+	set    action = iota // set the value of a cookie
+	remove               // delete the cookies matching a regexp
+	rename               // rename a cookie
+)
+
+// Cookie changes the cookies of the request.
+type Cookie struct {
+	rule   *types.Rule
+	value  *template.Template
+	action action
+}
+
+// NewSet returns a Cookie handler setting the value of a request cookie.
+func NewSet(rule types.Rule) (types.Handler, error) {
+	return newCookie(rule, set)
+}
+
+// NewDelete returns a Cookie handler deleting the request cookies matching a regexp.
+func NewDelete(rule types.Rule) (types.Handler, error) {
+	return newCookie(rule, remove)
+}
+
+// NewRename returns a Cookie handler renaming a request cookie.
+func NewRename(rule types.Rule) (types.Handler, error) {
+	return newCookie(rule, rename)
+}
+
+// nameRegexp compiles a regex matching cookie names.
+// Cookie names are matched as a whole, case-sensitively.
+func nameRegexp(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("cookie name %q: %w", expr, err)
+	}
+
+	return re, nil
+}
+
+func newCookie(rule types.Rule, cookieAction action) (*Cookie, error) {
+	value, err := template.Parse(rule.Value)
+	if err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+
+	if cookieAction == remove {
+		re, err := nameRegexp(rule.Cookie)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+		}
+
+		rule.Regexp = re
+	}
+
+	return &Cookie{rule: &rule, value: value, action: cookieAction}, nil
+}
+
+func (c *Cookie) Validate() error {
+	if c.rule.Cookie == "" || (c.action == rename && c.rule.Value == "") {
+		return types.ErrMissingRequiredFields
+	}
+
+	if c.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (c *Cookie) Handle(_ http.ResponseWriter, req *http.Request) {
+	cookies := req.Cookies()
+
+	var changed bool
+
+	switch c.action {
+	case set:
+		cookies, changed = c.set(req, cookies), true
+	case remove:
+		cookies, changed = c.remove(cookies)
+	case rename:
+		cookies, changed = c.rename(req, cookies)
+	}
+
+	if !changed {
+		return
+	}
+
+	req.Header.Del("Cookie")
+
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+// set replaces the value of the cookie, or adds it if it is missing.
+func (c *Cookie) set(req *http.Request, cookies []*http.Cookie) []*http.Cookie {
+	value := header.Resolve(req, c.value.Execute(req), c.rule.HeaderPrefix)
+
+	for index, cookie := range cookies {
+		if cookie.Name == c.rule.Cookie {
+			cookie.Value = value
+
+			return append(cookies[:index+1], withoutCookie(cookies[index+1:], c.rule.Cookie)...)
+		}
+	}
+
+	cookie := new(http.Cookie)
+	cookie.Name = c.rule.Cookie
+	cookie.Value = value
+
+	return append(cookies, cookie)
+}
+
+// remove deletes the cookies matching the rule.
+func (c *Cookie) remove(cookies []*http.Cookie) ([]*http.Cookie, bool) {
+	kept := make([]*http.Cookie, 0, len(cookies))
+
+	for _, cookie := range cookies {
+		if !c.rule.Regexp.MatchString(cookie.Name) {
+			kept = append(kept, cookie)
+		}
+	}
+
+	return kept, len(kept) != len(cookies)
+}
+
+// rename renames the cookie, replacing any cookie already having the new name.
+func (c *Cookie) rename(req *http.Request, cookies []*http.Cookie) ([]*http.Cookie, bool) {
+	name := header.Resolve(req, c.value.Execute(req), c.rule.HeaderPrefix)
+	if name == "" || name == c.rule.Cookie {
+		return cookies, false
+	}
+
+	found := false
+
+	for _, cookie := range cookies {
+		if cookie.Name == c.rule.Cookie {
+			found = true
+		}
+	}
+
+	if !found {
+		return cookies, false
+	}
+
+	cookies = withoutCookie(cookies, name)
+
+	for _, cookie := range cookies {
+		if cookie.Name == c.rule.Cookie {
+			cookie.Name = name
+		}
+	}
+
+	return cookies, true
+}
+
+// withoutCookie returns the cookies not named name.
+func withoutCookie(cookies []*http.Cookie, name string) []*http.Cookie {
+	kept := make([]*http.Cookie, 0, len(cookies))
+
+	for _, cookie := range cookies {
+		if cookie.Name != name {
+			kept = append(kept, cookie)
+		}
+	}
+
+	return kept
+}
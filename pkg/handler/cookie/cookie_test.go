@@ -0,0 +1,276 @@
+package cookie_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/cookie"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestCookieHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		newHandler     func(types.Rule) (types.Handler, error)
+		rule           types.Rule
+		requestHeaders map[string]string
+		want           []string
+	}{
+		{
+			name:       "set a cookie",
+			newHandler: cookie.NewSet,
+			rule: types.Rule{
+				Cookie: "theme",
+				Value:  "dark",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "session=1234",
+			},
+			want: []string{"session=1234; theme=dark"},
+		},
+		{
+			name:       "set an existing cookie",
+			newHandler: cookie.NewSet,
+			rule: types.Rule{
+				Cookie: "theme",
+				Value:  "dark",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "theme=light; session=1234; theme=blue",
+			},
+			want: []string{"theme=dark; session=1234"},
+		},
+		{
+			name:       "set a cookie without cookies",
+			newHandler: cookie.NewSet,
+			rule: types.Rule{
+				Cookie: "theme",
+				Value:  `{{ .Header "X-Theme" }}`,
+			},
+			requestHeaders: map[string]string{
+				"X-Theme": "dark",
+			},
+			want: []string{"theme=dark"},
+		},
+		{
+			name:       "delete a cookie",
+			newHandler: cookie.NewDelete,
+			rule: types.Rule{
+				Cookie: "session",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "session=1234; theme=dark; session_id=5678",
+			},
+			want: []string{"theme=dark; session_id=5678"},
+		},
+		{
+			name:       "delete cookies matching a regexp",
+			newHandler: cookie.NewDelete,
+			rule: types.Rule{
+				Cookie: "_ga.*",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "_ga=1; session=1234; _gat_UA=2",
+			},
+			want: []string{"session=1234"},
+		},
+		{
+			name:       "delete the last cookie",
+			newHandler: cookie.NewDelete,
+			rule: types.Rule{
+				Cookie: "session",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "session=1234",
+			},
+			want: nil,
+		},
+		{
+			name:       "delete a missing cookie",
+			newHandler: cookie.NewDelete,
+			rule: types.Rule{
+				Cookie: "session",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "theme=dark;lang=en",
+			},
+			want: []string{"theme=dark;lang=en"},
+		},
+		{
+			name:       "rename a cookie",
+			newHandler: cookie.NewRename,
+			rule: types.Rule{
+				Cookie: "sid",
+				Value:  "session",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "theme=dark; sid=1234; session=5678",
+			},
+			want: []string{"theme=dark; session=1234"},
+		},
+		{
+			name:       "rename a missing cookie",
+			newHandler: cookie.NewRename,
+			rule: types.Rule{
+				Cookie: "sid",
+				Value:  "session",
+			},
+			requestHeaders: map[string]string{
+				"Cookie": "session=5678",
+			},
+			want: []string{"session=5678"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVal := range test.requestHeaders {
+				req.Header.Add(hName, hVal)
+			}
+
+			cookieHandler, err := test.newHandler(test.rule)
+			require.NoError(t, err)
+
+			cookieHandler.Handle(nil, req)
+
+			assert.Equal(t, test.want, req.Header.Values("Cookie"))
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		newHandler      func(types.Rule) (types.Handler, error)
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			newHandler:      cookie.NewSet,
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid template",
+			rule: types.Rule{
+				Cookie: "theme",
+				Value:  "{{ .Foo }}",
+			},
+			newHandler: cookie.NewSet,
+			wantNewErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Cookie: "(",
+			},
+			newHandler: cookie.NewDelete,
+			wantNewErr: true,
+		},
+		{
+			name: "rename without value",
+			rule: types.Rule{
+				Cookie: "sid",
+			},
+			newHandler:      cookie.NewRename,
+			wantValidateErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Cookie:        "theme",
+				Value:         "dark",
+				SetOnResponse: true,
+			},
+			newHandler:      cookie.NewSet,
+			wantValidateErr: true,
+		},
+		{
+			name: "attributes without attributes",
+			rule: types.Rule{
+				SetOnResponse: true,
+			},
+			newHandler:      cookie.NewAttributes,
+			wantValidateErr: true,
+		},
+		{
+			name: "attributes on request",
+			rule: types.Rule{
+				Attributes: &types.SetCookieAttributes{Secure: true},
+			},
+			newHandler:      cookie.NewAttributes,
+			wantValidateErr: true,
+		},
+		{
+			name: "attributes with invalid SameSite",
+			rule: types.Rule{
+				Attributes:    &types.SetCookieAttributes{SameSite: "Loose"},
+				SetOnResponse: true,
+			},
+			newHandler:      cookie.NewAttributes,
+			wantValidateErr: true,
+		},
+		{
+			name: "attributes with invalid regexp",
+			rule: types.Rule{
+				Cookie:        "(",
+				Attributes:    &types.SetCookieAttributes{Secure: true},
+				SetOnResponse: true,
+			},
+			newHandler: cookie.NewAttributes,
+			wantNewErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Cookie: "theme",
+				Value:  "dark",
+			},
+			newHandler: cookie.NewSet,
+		},
+		{
+			name: "valid attributes rule",
+			rule: types.Rule{
+				Attributes:    &types.SetCookieAttributes{SameSite: "Strict"},
+				SetOnResponse: true,
+			},
+			newHandler: cookie.NewAttributes,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cookieHandler, err := test.newHandler(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = cookieHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package copier
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+type Copy struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	re, err := regexp.Compile(rule.Header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+	}
+
+	rule.Regexp = re
+
+	return &Copy{rule: &rule}, nil
+}
+
+func (c *Copy) Validate() error {
+	if c.rule.Value == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	return nil
+}
+
+func (c *Copy) Handle(rw http.ResponseWriter, req *http.Request) {
+	var values []string
+	if c.rule.SetOnResponse && !c.rule.FromRequest {
+		values = c.headerValues(rw.Header())
+	} else {
+		values = c.requestValues(req)
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	if c.rule.SetOnResponse {
+		rw.Header().Del(c.rule.Value)
+
+		for _, val := range values {
+			rw.Header().Add(c.rule.Value, val)
+		}
+
+		return
+	}
+
+	header.Replace(req, c.rule.Value, values)
+}
+
+// requestValues returns the values of every request header matching the rule, including the Host.
+func (c *Copy) requestValues(req *http.Request) []string {
+	values := c.headerValues(req.Header)
+
+	if c.isSource("Host") && req.Host != "" {
+		values = append(values, req.Host)
+	}
+
+	return values
+}
+
+// headerValues returns the values of every header matching the rule, sorted by header name.
+func (c *Copy) headerValues(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		if c.isSource(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var values []string
+	for _, name := range names {
+		values = append(values, headers[name]...)
+	}
+
+	return values
+}
+
+// isSource reports whether the header should be copied, the target header is
+// never a source unless it is read from the request to change the response.
+func (c *Copy) isSource(name string) bool {
+	if !c.rule.Regexp.MatchString(name) {
+		return false
+	}
+
+	if c.rule.SetOnResponse && c.rule.FromRequest {
+		return true
+	}
+
+	return !strings.EqualFold(c.rule.Value, name)
+}
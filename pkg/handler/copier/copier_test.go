@@ -0,0 +1,274 @@
+package copier_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/copier"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestCopyHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		requestHeaders  map[string][]string
+		responseHeaders map[string][]string
+		wantOnRequest   map[string][]string
+		wantOnResponse  map[string][]string
+		expectedHost    string
+	}{
+		{
+			name: "no match",
+			rule: types.Rule{
+				Header: "X-Not-Existing",
+				Value:  "X-Copy",
+			},
+			requestHeaders: map[string][]string{
+				"Foo": {"Bar"},
+			},
+			wantOnRequest: map[string][]string{
+				"Foo":    {"Bar"},
+				"X-Copy": nil,
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy one header",
+			rule: types.Rule{
+				Header: "X-Request-Id",
+				Value:  "X-Correlation-Id",
+			},
+			requestHeaders: map[string][]string{
+				"X-Request-Id": {"1234"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Request-Id":     {"1234"},
+				"X-Correlation-Id": {"1234"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy multi-valued header over existing one",
+			rule: types.Rule{
+				Header: "X-Source",
+				Value:  "X-Target",
+			},
+			requestHeaders: map[string][]string{
+				"X-Source": {"foo", "bar"},
+				"X-Target": {"baz"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Source": {"foo", "bar"},
+				"X-Target": {"foo", "bar"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy headers matching a regex",
+			rule: types.Rule{
+				Header: "^X-Source-",
+				Value:  "X-Target",
+			},
+			requestHeaders: map[string][]string{
+				"X-Source-B": {"bar"},
+				"X-Source-A": {"foo"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Source-A": {"foo"},
+				"X-Source-B": {"bar"},
+				"X-Target":   {"foo", "bar"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy Host",
+			rule: types.Rule{
+				Header: "^Host$",
+				Value:  "X-Forwarded-Host",
+			},
+			wantOnRequest: map[string][]string{
+				"X-Forwarded-Host": {"example.com"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy to Host",
+			rule: types.Rule{
+				Header: "X-Host",
+				Value:  "Host",
+			},
+			requestHeaders: map[string][]string{
+				"X-Host": {"example.org"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Host": {"example.org"},
+			},
+			expectedHost: "example.org",
+		},
+		{
+			name: "copy several values to Host",
+			rule: types.Rule{
+				Header: "X-Host",
+				Value:  "Host",
+			},
+			requestHeaders: map[string][]string{
+				"X-Host": {"example.org", "example.net"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Host": {"example.org", "example.net"},
+			},
+			expectedHost: "example.org",
+		},
+		{
+			name: "copy on response",
+			rule: types.Rule{
+				Header:        "X-Source",
+				Value:         "X-Target",
+				SetOnResponse: true,
+			},
+			requestHeaders: map[string][]string{
+				"X-Source": {"request"},
+			},
+			responseHeaders: map[string][]string{
+				"X-Source": {"response"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Target": nil,
+			},
+			wantOnResponse: map[string][]string{
+				"X-Source": {"response"},
+				"X-Target": {"response"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "copy from request to response",
+			rule: types.Rule{
+				Header:        "X-Request-Id",
+				Value:         "X-Request-Id",
+				SetOnResponse: true,
+				FromRequest:   true,
+			},
+			requestHeaders: map[string][]string{
+				"X-Request-Id": {"1234"},
+			},
+			wantOnRequest: map[string][]string{
+				"X-Request-Id": {"1234"},
+			},
+			wantOnResponse: map[string][]string{
+				"X-Request-Id": {"1234"},
+			},
+			expectedHost: "example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVals := range test.requestHeaders {
+				for _, hVal := range hVals {
+					req.Header.Add(hName, hVal)
+				}
+			}
+
+			rw := httptest.NewRecorder()
+
+			for hName, hVals := range test.responseHeaders {
+				for _, hVal := range hVals {
+					rw.Header().Add(hName, hVal)
+				}
+			}
+
+			copyHandler, err := copier.New(test.rule)
+			require.NoError(t, err)
+
+			copyHandler.Handle(rw, req)
+
+			for hName, hVals := range test.wantOnRequest {
+				assert.Equalf(t, hVals, req.Header.Values(hName), "request header %q", hName)
+			}
+
+			for hName, hVals := range test.wantOnResponse {
+				assert.Equalf(t, hVals, rw.Header().Values(hName), "response header %q", hName)
+			}
+
+			assert.Equal(t, test.expectedHost, req.Host)
+			assert.Equal(t, "example.com", req.URL.Host)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "missing header value",
+			rule: types.Rule{
+				Header: ".",
+				Type:   types.Copy,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Header: "(",
+				Type:   types.Copy,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "not-empty",
+				Value:  "not-empty",
+				Type:   types.Copy,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			copyHandler, err := copier.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = copyHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
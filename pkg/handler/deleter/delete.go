@@ -1,7 +1,10 @@
 package deleter
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/tomMoulard/htransformation/pkg/types"
 	"github.com/tomMoulard/htransformation/pkg/utils/header"
@@ -12,19 +15,48 @@ type Deleter struct {
 }
 
 func New(rule types.Rule) (types.Handler, error) {
+	re, err := header.NameRegexp(globExpr(rule.Header))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+	}
+
+	rule.Regexp = re
+
 	return &Deleter{rule: &rule}, nil
 }
 
+// globExpr returns the regex of a glob, where * matches any characters and ? a single one.
+// Other characters, such as the dots allowed in header names, are matched literally.
+func globExpr(glob string) string {
+	return strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(glob))
+}
+
 func (d *Deleter) Validate() error {
+	if d.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
 	return nil
 }
 
 func (d *Deleter) Handle(rw http.ResponseWriter, req *http.Request) {
 	if d.rule.SetOnResponse {
-		rw.Header().Del(d.rule.Header)
+		for name := range rw.Header() {
+			if d.rule.Regexp.MatchString(name) {
+				rw.Header().Del(name)
+			}
+		}
 
 		return
 	}
 
-	header.Delete(req, d.rule.Header)
+	for name := range req.Header {
+		if d.rule.Regexp.MatchString(name) {
+			req.Header.Del(name)
+		}
+	}
+
+	if d.rule.Regexp.MatchString("Host") {
+		header.Delete(req, "Host")
+	}
 }
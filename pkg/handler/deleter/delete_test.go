@@ -55,6 +55,83 @@ func TestDeleteHandler(t *testing.T) {
 			},
 			expectedHost: "",
 		},
+		{
+			name: "Remove headers matching a glob",
+			rule: types.Rule{
+				Header: "X-Internal-*",
+			},
+			requestHeaders: map[string]string{
+				"Foo":           "Bar",
+				"X-Internal-Id": "1234",
+				"X-Internal-Ip": "10.0.0.1",
+			},
+			expectedHeaders: map[string]string{
+				"Foo":           "Bar",
+				"X-Internal-Id": "",
+				"X-Internal-Ip": "",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Header names are matched as a whole, case-insensitively",
+			rule: types.Rule{
+				Header: "x-test",
+			},
+			requestHeaders: map[string]string{
+				"X-Test":      "Bar",
+				"X-Test-Keep": "Bar",
+				"X-No-X-Test": "Bar",
+			},
+			expectedHeaders: map[string]string{
+				"X-Test":      "",
+				"X-Test-Keep": "Bar",
+				"X-No-X-Test": "Bar",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Dots are matched literally",
+			rule: types.Rule{
+				Header: "X-Foo.Bar",
+			},
+			requestHeaders: map[string]string{
+				"X-Foo.Bar": "Bar",
+				"X-Foo-Bar": "Bar",
+			},
+			expectedHeaders: map[string]string{
+				"X-Foo.Bar": "",
+				"X-Foo-Bar": "Bar",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Question mark matching a single character",
+			rule: types.Rule{
+				Header: "X-Test-?",
+			},
+			requestHeaders: map[string]string{
+				"X-Test-1":  "Bar",
+				"X-Test-10": "Bar",
+			},
+			expectedHeaders: map[string]string{
+				"X-Test-1":  "",
+				"X-Test-10": "Bar",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Remove host header matching a glob",
+			rule: types.Rule{
+				Header: "*Host",
+			},
+			requestHeaders: map[string]string{
+				"X-Forwarded-Host": "example.org",
+			},
+			expectedHeaders: map[string]string{
+				"X-Forwarded-Host": "",
+			},
+			expectedHost: "",
+		},
 	}
 
 	for _, test := range tests {
@@ -119,6 +196,38 @@ func TestDeleteHandlerOnResponse(t *testing.T) {
 				"Foo": "Bar",
 			},
 		},
+		{
+			name: "Remove one header case-insensitively",
+			rule: types.Rule{
+				Header:        "x-test",
+				SetOnResponse: true,
+			},
+			requestHeaders: map[string]string{
+				"Foo":    "Bar",
+				"X-Test": "Bar",
+			},
+			want: map[string]string{
+				"Foo":    "Bar",
+				"X-Test": "",
+			},
+		},
+		{
+			name: "Remove headers matching a glob",
+			rule: types.Rule{
+				Header:        "X-Amzn-*",
+				SetOnResponse: true,
+			},
+			requestHeaders: map[string]string{
+				"Foo":              "Bar",
+				"X-Amzn-Requestid": "1234",
+				"X-Amzn-Trace-Id":  "Root=1",
+			},
+			want: map[string]string{
+				"Foo":              "Bar",
+				"X-Amzn-Requestid": "",
+				"X-Amzn-Trace-Id":  "",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -153,7 +262,7 @@ func TestValidation(t *testing.T) {
 	}{
 		{
 			name:    "no rules",
-			wantErr: false,
+			wantErr: true,
 		},
 		{
 			name: "valid rule",
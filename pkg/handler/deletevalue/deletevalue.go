@@ -0,0 +1,99 @@
+package deletevalue
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+type DeleteValue struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	// elements are matched as a whole, leading and trailing spaces excluded.
+	re, err := regexp.Compile("^(?:" + rule.Value + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+	}
+
+	rule.Regexp = re
+
+	return &DeleteValue{rule: &rule}, nil
+}
+
+func (d *DeleteValue) Validate() error {
+	if d.rule.Header == "" || d.rule.Value == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	return nil
+}
+
+func (d *DeleteValue) Handle(rw http.ResponseWriter, req *http.Request) {
+	var existing []string
+	if d.rule.SetOnResponse {
+		existing = rw.Header().Values(d.rule.Header)
+	} else {
+		existing = header.Values(req, d.rule.Header)
+	}
+
+	if len(existing) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(existing))
+	changed := false
+
+	for _, value := range existing {
+		filtered := d.filter(value)
+		changed = changed || filtered != value
+
+		if filtered != "" {
+			values = append(values, filtered)
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	if d.rule.SetOnResponse {
+		rw.Header().Del(d.rule.Header)
+
+		for _, value := range values {
+			rw.Header().Add(d.rule.Header, value)
+		}
+
+		return
+	}
+
+	header.Replace(req, d.rule.Header, values)
+}
+
+// filter removes the elements matching the rule from a header value, it
+// returns an empty string if nothing remains.
+func (d *DeleteValue) filter(value string) string {
+	if d.rule.Sep == "" {
+		if d.rule.Regexp.MatchString(strings.TrimSpace(value)) {
+			return ""
+		}
+
+		return value
+	}
+
+	elements := strings.Split(value, d.rule.Sep)
+	kept := make([]string, 0, len(elements))
+
+	for _, element := range elements {
+		if !d.rule.Regexp.MatchString(strings.TrimSpace(element)) {
+			kept = append(kept, element)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(kept, d.rule.Sep))
+}
@@ -0,0 +1,292 @@
+package deletevalue_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/deletevalue"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestDeleteValueHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		rule           types.Rule
+		requestHeaders map[string][]string
+		want           map[string][]string
+		expectedHost   string
+	}{
+		{
+			name: "remove a list element",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "gzip",
+				Sep:    ",",
+			},
+			requestHeaders: map[string][]string{
+				"Accept-Encoding": {"gzip, deflate, br"},
+			},
+			want: map[string][]string{
+				"Accept-Encoding": {"deflate, br"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "elements are matched as a whole",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "gzip",
+				Sep:    ",",
+			},
+			requestHeaders: map[string][]string{
+				"Accept-Encoding": {"x-gzip, gzip;q=0.5"},
+			},
+			want: map[string][]string{
+				"Accept-Encoding": {"x-gzip, gzip;q=0.5"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "remove a cookie",
+			rule: types.Rule{
+				Header: "Cookie",
+				Value:  "session=.*",
+				Sep:    ";",
+			},
+			requestHeaders: map[string][]string{
+				"Cookie": {"session=1234; theme=dark", "lang=en; session=5678"},
+			},
+			want: map[string][]string{
+				"Cookie": {"theme=dark", "lang=en"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "remove a value entry",
+			rule: types.Rule{
+				Header: "Via",
+				Value:  `1\.1 internal-proxy`,
+			},
+			requestHeaders: map[string][]string{
+				"Via": {"1.1 cdn", "1.1 internal-proxy", "1.1 lb"},
+			},
+			want: map[string][]string{
+				"Via": {"1.1 cdn", "1.1 lb"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "delete the header if nothing remains",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "gzip|br",
+				Sep:    ",",
+			},
+			requestHeaders: map[string][]string{
+				"Accept-Encoding": {"gzip, br", "br"},
+			},
+			want: map[string][]string{
+				"Accept-Encoding": nil,
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "missing header",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "gzip",
+				Sep:    ",",
+			},
+			requestHeaders: map[string][]string{
+				"Foo": {"gzip"},
+			},
+			want: map[string][]string{
+				"Accept-Encoding": nil,
+				"Foo":             {"gzip"},
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Host header",
+			rule: types.Rule{
+				Header: "Host",
+				Value:  `example\.com`,
+			},
+			expectedHost: "",
+		},
+		{
+			name: "Host element",
+			rule: types.Rule{
+				Header: "Host",
+				Value:  "example",
+				Sep:    ".",
+			},
+			expectedHost: "com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVals := range test.requestHeaders {
+				for _, hVal := range hVals {
+					req.Header.Add(hName, hVal)
+				}
+			}
+
+			deleteValueHandler, err := deletevalue.New(test.rule)
+			require.NoError(t, err)
+
+			deleteValueHandler.Handle(nil, req)
+
+			for hName, hVals := range test.want {
+				assert.Equal(t, hVals, req.Header.Values(hName))
+			}
+
+			assert.Equal(t, test.expectedHost, req.Host)
+		})
+	}
+}
+
+func TestDeleteValueHandlerOnResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		responseHeaders map[string][]string
+		want            map[string][]string
+	}{
+		{
+			name: "remove a list element",
+			rule: types.Rule{
+				Header:        "Vary",
+				Value:         "Cookie",
+				Sep:           ",",
+				SetOnResponse: true,
+			},
+			responseHeaders: map[string][]string{
+				"Vary": {"Accept-Encoding, Cookie, Origin"},
+			},
+			want: map[string][]string{
+				"Vary": {"Accept-Encoding, Origin"},
+			},
+		},
+		{
+			name: "delete the header if nothing remains",
+			rule: types.Rule{
+				Header:        "Vary",
+				Value:         "Cookie",
+				SetOnResponse: true,
+			},
+			responseHeaders: map[string][]string{
+				"Vary": {"Cookie"},
+			},
+			want: map[string][]string{
+				"Vary": nil,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			for hName, hVals := range test.responseHeaders {
+				for _, hVal := range hVals {
+					rw.Header().Add(hName, hVal)
+				}
+			}
+
+			deleteValueHandler, err := deletevalue.New(test.rule)
+			require.NoError(t, err)
+
+			deleteValueHandler.Handle(rw, req)
+
+			for hName, hVals := range test.want {
+				assert.Equal(t, hVals, rw.Header().Values(hName))
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "missing value",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Type:   types.DeleteValue,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "(",
+				Type:   types.DeleteValue,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "Accept-Encoding",
+				Value:  "gzip",
+				Sep:    ",",
+				Type:   types.DeleteValue,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			deleteValueHandler, err := deletevalue.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = deleteValueHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
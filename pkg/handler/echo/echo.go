@@ -0,0 +1,56 @@
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+type Echo struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	re, err := regexp.Compile(rule.Header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+	}
+
+	rule.Regexp = re
+
+	return &Echo{rule: &rule}, nil
+}
+
+func (e *Echo) Validate() error {
+	if e.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	if !e.rule.SetOnResponse {
+		return types.ErrResponseOnly
+	}
+
+	return nil
+}
+
+func (e *Echo) Handle(rw http.ResponseWriter, req *http.Request) {
+	headers := req.Header
+	if original := state.FromRequest(req).OriginalHeader; !e.rule.Transformed && original != nil {
+		headers = original
+	}
+
+	for headerName, headerValues := range headers {
+		if matched := e.rule.Regexp.MatchString(headerName); !matched {
+			continue
+		}
+
+		rw.Header().Del(headerName)
+
+		for _, val := range headerValues {
+			rw.Header().Add(headerName, val)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package echo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/echo"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+func TestEchoHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		originalHeaders map[string][]string
+		requestHeaders  map[string][]string
+		responseHeaders map[string][]string
+		wantOnResponse  map[string][]string
+	}{
+		{
+			name: "no match",
+			rule: types.Rule{
+				Header: "X-Not-Existing",
+			},
+			requestHeaders: map[string][]string{
+				"Foo": {"Bar"},
+			},
+			wantOnResponse: map[string][]string{
+				"Foo": nil,
+			},
+		},
+		{
+			name: "echo request headers",
+			rule: types.Rule{
+				Header: "^(X-Request-Id|Traceparent)$",
+			},
+			requestHeaders: map[string][]string{
+				"Foo":          {"Bar"},
+				"X-Request-Id": {"1234"},
+				"Traceparent":  {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+			responseHeaders: map[string][]string{
+				"X-Request-Id": {"upstream"},
+			},
+			wantOnResponse: map[string][]string{
+				"Foo":          nil,
+				"X-Request-Id": {"1234"},
+				"Traceparent":  {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+		},
+		{
+			name: "echo multi-valued request header",
+			rule: types.Rule{
+				Header: "^X-Tenant-",
+			},
+			requestHeaders: map[string][]string{
+				"X-Tenant-Id": {"foo", "bar"},
+			},
+			wantOnResponse: map[string][]string{
+				"X-Tenant-Id": {"foo", "bar"},
+			},
+		},
+		{
+			name: "echo original request headers",
+			rule: types.Rule{
+				Header: "^X-Request-Id$",
+			},
+			originalHeaders: map[string][]string{
+				"X-Request-Id": {"original"},
+			},
+			requestHeaders: map[string][]string{
+				"X-Request-Id": {"transformed"},
+			},
+			wantOnResponse: map[string][]string{
+				"X-Request-Id": {"original"},
+			},
+		},
+		{
+			name: "echo transformed request headers",
+			rule: types.Rule{
+				Header:      "^X-Request-Id$",
+				Transformed: true,
+			},
+			originalHeaders: map[string][]string{
+				"X-Request-Id": {"original"},
+			},
+			requestHeaders: map[string][]string{
+				"X-Request-Id": {"transformed"},
+			},
+			wantOnResponse: map[string][]string{
+				"X-Request-Id": {"transformed"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVals := range test.requestHeaders {
+				for _, hVal := range hVals {
+					req.Header.Add(hName, hVal)
+				}
+			}
+
+			if test.originalHeaders != nil {
+				req = req.WithContext(state.NewContext(req.Context(), &state.State{
+					OriginalHeader: test.originalHeaders,
+					StatusCode:     0,
+				}))
+			}
+
+			rw := httptest.NewRecorder()
+
+			for hName, hVals := range test.responseHeaders {
+				for _, hVal := range hVals {
+					rw.Header().Add(hName, hVal)
+				}
+			}
+
+			test.rule.SetOnResponse = true
+
+			echoHandler, err := echo.New(test.rule)
+			require.NoError(t, err)
+
+			echoHandler.Handle(rw, req)
+
+			for hName, hVals := range test.wantOnResponse {
+				assert.Equalf(t, hVals, rw.Header().Values(hName), "response header %q", hName)
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Header:        "(",
+				Type:          types.Echo,
+				SetOnResponse: true,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "on request",
+			rule: types.Rule{
+				Header: "not-empty",
+				Type:   types.Echo,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:        "not-empty",
+				Type:          types.Echo,
+				SetOnResponse: true,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			echoHandler, err := echo.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = echoHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
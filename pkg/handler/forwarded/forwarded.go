@@ -0,0 +1,191 @@
+package forwarded
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/forwarded"
+)
+
+// Forwarded writes the forwarding chain of the request in the Forwarded or the X-Forwarded-* headers, reading it
+// from the other family if needed, and optionally appends this hop.
+type Forwarded struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Format == "" {
+		rule.Format = types.ForwardedRFC7239
+	}
+
+	return &Forwarded{rule: &rule}, nil
+}
+
+func (f *Forwarded) Validate() error {
+	if f.rule.Format != types.ForwardedRFC7239 && f.rule.Format != types.ForwardedX {
+		return fmt.Errorf("%w: Format %q", types.ErrInvalidOption, f.rule.Format)
+	}
+
+	if f.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (f *Forwarded) Handle(_ http.ResponseWriter, req *http.Request) {
+	source := types.ForwardedX
+	if f.rule.Format == types.ForwardedX {
+		source = types.ForwardedRFC7239
+	}
+
+	format := f.rule.Format
+	if !present(req.Header, format) {
+		format = source
+	}
+
+	elements, ok := read(req.Header, format)
+	if !ok {
+		return
+	}
+
+	if f.rule.AppendHop {
+		elements = append(elements, f.hop(req))
+	}
+
+	if f.rule.RemoveSource {
+		for _, name := range headerNames(source) {
+			req.Header.Del(name)
+		}
+	}
+
+	write(req.Header, f.rule.Format, elements)
+}
+
+// hop returns the element of the request received by this proxy.
+func (f *Forwarded) hop(req *http.Request) forwarded.Element {
+	proto := "http"
+	if req.TLS != nil {
+		proto = "https"
+	}
+
+	return forwarded.Element{
+		For:        forwarded.Node(forwarded.Address(req.RemoteAddr)),
+		By:         f.rule.Value,
+		Proto:      proto,
+		Host:       req.Host,
+		Extensions: nil,
+	}
+}
+
+func headerNames(format types.ForwardedFormat) []string {
+	if format == types.ForwardedX {
+		return []string{"X-Forwarded-For", "X-Forwarded-Proto", "X-Forwarded-Host"}
+	}
+
+	return []string{"Forwarded"}
+}
+
+func present(headers http.Header, format types.ForwardedFormat) bool {
+	for _, name := range headerNames(format) {
+		if len(headers.Values(name)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// read returns the forwarding chain of the headers, it returns false if the Forwarded header is malformed.
+func read(headers http.Header, format types.ForwardedFormat) ([]forwarded.Element, bool) {
+	if format == types.ForwardedRFC7239 {
+		return forwarded.Parse(headers.Values("Forwarded"))
+	}
+
+	fors := list(headers.Values("X-Forwarded-For"))
+	protos := list(headers.Values("X-Forwarded-Proto"))
+	hosts := list(headers.Values("X-Forwarded-Host"))
+
+	// the n-th proto and host are the ones of the request received by the n-th proxy.
+	length := len(fors)
+	for _, values := range [][]string{protos, hosts} {
+		if len(values) > length {
+			length = len(values)
+		}
+	}
+
+	elements := make([]forwarded.Element, length)
+
+	for i, value := range fors {
+		elements[i].For = forwarded.Node(value)
+	}
+
+	for i, value := range protos {
+		elements[i].Proto = value
+	}
+
+	for i, value := range hosts {
+		elements[i].Host = value
+	}
+
+	return elements, true
+}
+
+// write replaces the headers of the format by the forwarding chain.
+func write(headers http.Header, format types.ForwardedFormat, elements []forwarded.Element) {
+	for _, name := range headerNames(format) {
+		headers.Del(name)
+	}
+
+	if format == types.ForwardedRFC7239 {
+		if value := forwarded.Format(elements); value != "" {
+			headers.Set("Forwarded", value)
+		}
+
+		return
+	}
+
+	var fors []string
+
+	// Obfuscated identifiers and "unknown" are not addresses and have no place in X-Forwarded-For.
+	for _, element := range elements {
+		if address := forwarded.Address(element.For); net.ParseIP(address) != nil {
+			fors = append(fors, address)
+		}
+	}
+
+	if fors != nil {
+		headers.Set("X-Forwarded-For", strings.Join(fors, ", "))
+	}
+
+	if len(elements) == 0 {
+		return
+	}
+
+	// X-Forwarded-Proto and X-Forwarded-Host describe the request sent by the client, the first hop.
+	if elements[0].Proto != "" {
+		headers.Set("X-Forwarded-Proto", elements[0].Proto)
+	}
+
+	if elements[0].Host != "" {
+		headers.Set("X-Forwarded-Host", elements[0].Host)
+	}
+}
+
+// list returns the elements of comma separated header values.
+func list(values []string) []string {
+	var elements []string
+
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				elements = append(elements, element)
+			}
+		}
+	}
+
+	return elements
+}
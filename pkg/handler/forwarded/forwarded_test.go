@@ -0,0 +1,219 @@
+package forwarded_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/forwarded"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestForwardedHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		remoteAddr  string
+		tls         bool
+		headers     http.Header
+		wantHeaders http.Header
+	}{
+		{
+			name: "X-Forwarded to Forwarded",
+			headers: http.Header{
+				"X-Forwarded-For":   {"192.0.2.43, 2001:db8:cafe::17"},
+				"X-Forwarded-Proto": {"https"},
+				"X-Forwarded-Host":  {"example.com"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded":         {`for=192.0.2.43;proto=https;host=example.com, for="[2001:db8:cafe::17]"`},
+				"X-Forwarded-For":   {"192.0.2.43, 2001:db8:cafe::17"},
+				"X-Forwarded-Proto": {"https"},
+				"X-Forwarded-Host":  {"example.com"},
+			},
+		},
+		{
+			name: "X-Forwarded to Forwarded removing the source",
+			rule: types.Rule{
+				RemoveSource: true,
+			},
+			headers: http.Header{
+				"X-Forwarded-For": {"192.0.2.43", "198.51.100.17"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded": {"for=192.0.2.43, for=198.51.100.17"},
+			},
+		},
+		{
+			name: "append hop to Forwarded",
+			rule: types.Rule{
+				Value:     "_proxy",
+				AppendHop: true,
+			},
+			remoteAddr: "[2001:db8:cafe::17]:4711",
+			tls:        true,
+			headers: http.Header{
+				"Forwarded":       {"for=192.0.2.43"},
+				"X-Forwarded-For": {"192.0.2.1"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded":       {`for=192.0.2.43, for="[2001:db8:cafe::17]";by=_proxy;proto=https;host=example.com`},
+				"X-Forwarded-For": {"192.0.2.1"},
+			},
+		},
+		{
+			name: "append hop without forwarding headers",
+			rule: types.Rule{
+				AppendHop: true,
+			},
+			remoteAddr: "192.0.2.43:47011",
+			headers:    http.Header{},
+			wantHeaders: http.Header{
+				"Forwarded": {"for=192.0.2.43;proto=http;host=example.com"},
+			},
+		},
+		{
+			name: "Forwarded to X-Forwarded",
+			rule: types.Rule{
+				Format:       types.ForwardedX,
+				RemoveSource: true,
+			},
+			headers: http.Header{
+				"Forwarded": {`for="[2001:db8:cafe::17]:4711";proto=https, for="192.0.2.43:47011";host=internal`},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For":   {"2001:db8:cafe::17, 192.0.2.43"},
+				"X-Forwarded-Proto": {"https"},
+			},
+		},
+		{
+			name: "Forwarded to X-Forwarded skipping identifiers",
+			rule: types.Rule{
+				Format: types.ForwardedX,
+			},
+			headers: http.Header{
+				"Forwarded": {`for=unknown, for=_hidden;proto=https;host=internal, for=192.0.2.43`},
+			},
+			wantHeaders: http.Header{
+				"Forwarded":       {`for=unknown, for=_hidden;proto=https;host=internal, for=192.0.2.43`},
+				"X-Forwarded-For": {"192.0.2.43"},
+			},
+		},
+		{
+			name: "append hop to X-Forwarded",
+			rule: types.Rule{
+				Format:    types.ForwardedX,
+				AppendHop: true,
+			},
+			remoteAddr: "198.51.100.17:1234",
+			headers: http.Header{
+				"X-Forwarded-For":  {"192.0.2.43"},
+				"X-Forwarded-Host": {"example.org"},
+			},
+			wantHeaders: http.Header{
+				"X-Forwarded-For":  {"192.0.2.43, 198.51.100.17"},
+				"X-Forwarded-Host": {"example.org"},
+			},
+		},
+		{
+			name: "malformed Forwarded",
+			rule: types.Rule{
+				Format:    types.ForwardedX,
+				AppendHop: true,
+			},
+			remoteAddr: "198.51.100.17:1234",
+			headers: http.Header{
+				"Forwarded": {"for=[2001:db8:cafe::17]"},
+			},
+			wantHeaders: http.Header{
+				"Forwarded": {"for=[2001:db8:cafe::17]"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.RemoteAddr = test.remoteAddr
+			req.Header = test.headers
+
+			if test.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			forwardedHandler, err := forwarded.New(test.rule)
+			require.NoError(t, err)
+
+			forwardedHandler.Handle(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.wantHeaders, req.Header)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: false,
+		},
+		{
+			name: "invalid format",
+			rule: types.Rule{
+				Format: "X-Real-IP",
+				Type:   types.Forwarded,
+			},
+			wantErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Type:          types.Forwarded,
+				SetOnResponse: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Format:    types.ForwardedX,
+				AppendHop: true,
+				Type:      types.Forwarded,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			forwardedHandler, err := forwarded.New(test.rule)
+			require.NoError(t, err)
+
+			err = forwardedHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
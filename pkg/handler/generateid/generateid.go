@@ -0,0 +1,97 @@
+package generateid
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/id"
+)
+
+// defaultHexLength is the length of the hexadecimal identifiers when no Length is given.
+const defaultHexLength = 32
+
+// GenerateID sets a request header to a generated identifier if it is missing.
+type GenerateID struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.IDFormat == "" {
+		rule.IDFormat = types.IDUUIDv4
+	}
+
+	if rule.IDFormat == types.IDHex && rule.Length == 0 {
+		rule.Length = defaultHexLength
+	}
+
+	return &GenerateID{rule: &rule}, nil
+}
+
+func (g *GenerateID) Validate() error {
+	if g.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	switch g.rule.IDFormat {
+	case types.IDUUIDv4, types.IDUUIDv7, types.IDULID:
+	case types.IDHex:
+		if g.rule.Length < 0 {
+			return fmt.Errorf("%w: Length %d", types.ErrInvalidOption, g.rule.Length)
+		}
+	default:
+		return fmt.Errorf("%w: IDFormat %q", types.ErrInvalidOption, g.rule.IDFormat)
+	}
+
+	if g.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (g *GenerateID) Handle(rw http.ResponseWriter, req *http.Request) {
+	value := ""
+	if values := header.Values(req, g.rule.Header); len(values) > 0 {
+		value = values[0]
+	}
+
+	if value == "" {
+		if value = g.generate(); value == "" {
+			return
+		}
+
+		header.Set(req, g.rule.Header, value)
+	}
+
+	if g.rule.CopyToResponse {
+		rw.Header().Set(g.rule.Header, value)
+	}
+}
+
+// generate returns a new identifier, or an empty string if the random source failed.
+func (g *GenerateID) generate() string {
+	var (
+		value string
+		err   error
+	)
+
+	switch g.rule.IDFormat {
+	case types.IDUUIDv7:
+		value, err = id.UUIDv7(time.Now())
+	case types.IDULID:
+		value, err = id.ULID(time.Now())
+	case types.IDHex:
+		value, err = id.Hex(g.rule.Length)
+	case types.IDUUIDv4:
+		value, err = id.UUIDv4()
+	}
+
+	if err != nil {
+		return ""
+	}
+
+	return value
+}
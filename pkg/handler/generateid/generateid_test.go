@@ -0,0 +1,193 @@
+package generateid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/generateid"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestGenerateIDHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rule          types.Rule
+		requestHeader string
+		wantRegexp    string
+		wantResponse  bool
+	}{
+		{
+			name: "default format",
+			rule: types.Rule{
+				Header: "X-Request-Id",
+			},
+			wantRegexp: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+		{
+			name: "UUIDv7",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDUUIDv7,
+			},
+			wantRegexp: `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+		{
+			name: "ULID",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDULID,
+			},
+			wantRegexp: `^[0-9A-HJKMNP-TV-Z]{26}$`,
+		},
+		{
+			name: "hex with default length",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDHex,
+			},
+			wantRegexp: `^[0-9a-f]{32}$`,
+		},
+		{
+			name: "hex with length",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDHex,
+				Length:   12,
+			},
+			wantRegexp: `^[0-9a-f]{12}$`,
+		},
+		{
+			name: "existing header",
+			rule: types.Rule{
+				Header: "X-Request-Id",
+			},
+			requestHeader: "1234",
+			wantRegexp:    `^1234$`,
+		},
+		{
+			name: "copy to response",
+			rule: types.Rule{
+				Header:         "X-Request-Id",
+				IDFormat:       types.IDHex,
+				CopyToResponse: true,
+			},
+			wantRegexp:   `^[0-9a-f]{32}$`,
+			wantResponse: true,
+		},
+		{
+			name: "copy existing header to response",
+			rule: types.Rule{
+				Header:         "X-Request-Id",
+				CopyToResponse: true,
+			},
+			requestHeader: "1234",
+			wantRegexp:    `^1234$`,
+			wantResponse:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			if test.requestHeader != "" {
+				req.Header.Set(test.rule.Header, test.requestHeader)
+			}
+
+			rw := httptest.NewRecorder()
+
+			generateIDHandler, err := generateid.New(test.rule)
+			require.NoError(t, err)
+
+			generateIDHandler.Handle(rw, req)
+
+			value := req.Header.Get(test.rule.Header)
+			assert.Equalf(t, true, regexp.MustCompile(test.wantRegexp).MatchString(value), "unexpected value %q", value)
+
+			if test.wantResponse {
+				assert.Equal(t, value, rw.Header().Get(test.rule.Header))
+			} else {
+				assert.Equal(t, "", rw.Header().Get(test.rule.Header))
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: true,
+		},
+		{
+			name: "invalid format",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: "UUIDv1",
+				Type:     types.GenerateID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid length",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDHex,
+				Length:   -1,
+				Type:     types.GenerateID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Request-Id",
+				Type:          types.GenerateID,
+				SetOnResponse: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:   "X-Request-Id",
+				IDFormat: types.IDULID,
+				Type:     types.GenerateID,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			generateIDHandler, err := generateid.New(test.rule)
+			require.NoError(t, err)
+
+			err = generateIDHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
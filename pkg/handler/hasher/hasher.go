@@ -0,0 +1,73 @@
+package hasher
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/encoding"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/signature"
+)
+
+// Hash replaces a header, or sets the Value header, with the salted hash of its values.
+type Hash struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Algorithm == "" {
+		rule.Algorithm = types.SHA256
+	}
+
+	if rule.Encoding == "" {
+		rule.Encoding = types.EncodingHex
+	}
+
+	if rule.Value == "" {
+		rule.Value = rule.Header
+	}
+
+	return &Hash{rule: &rule}, nil
+}
+
+func (h *Hash) Validate() error {
+	if h.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	if signature.NewHash(h.rule.Algorithm) == nil {
+		return fmt.Errorf("%w: Algorithm %q", types.ErrInvalidOption, h.rule.Algorithm)
+	}
+
+	if !encoding.Binary(h.rule.Encoding) {
+		return fmt.Errorf("%w: Encoding %q", types.ErrInvalidOption, h.rule.Encoding)
+	}
+
+	return nil
+}
+
+func (h *Hash) Handle(rw http.ResponseWriter, req *http.Request) {
+	var values []string
+	if h.rule.SetOnResponse {
+		values = rw.Header().Values(h.rule.Header)
+	} else {
+		values = header.Values(req, h.rule.Header)
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	sum := signature.Sum(h.rule.Algorithm, []byte(h.rule.Salt+strings.Join(values, ",")))
+	value := encoding.Encode(h.rule.Encoding, sum)
+
+	if h.rule.SetOnResponse {
+		rw.Header().Set(h.rule.Value, value)
+
+		return
+	}
+
+	header.Set(req, h.rule.Value, value)
+}
@@ -0,0 +1,171 @@
+package hasher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/hasher"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestHashHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		requestHeaders  http.Header
+		responseHeaders http.Header
+		want            http.Header
+	}{
+		{
+			name: "replace header",
+			rule: types.Rule{
+				Header: "X-User-Email",
+			},
+			requestHeaders: http.Header{
+				"X-User-Email": {"alice@example.com"},
+			},
+			want: http.Header{
+				"X-User-Email": {"ff8d9819fc0e12bf0d24892e45987e249a28dce836a85cad60e28eaaa8c6d976"},
+			},
+		},
+		{
+			name: "derive header with salt",
+			rule: types.Rule{
+				Header:   "X-User-Email",
+				Value:    "X-User-Id",
+				Salt:     "pepper",
+				Encoding: types.EncodingBase64,
+			},
+			requestHeaders: http.Header{
+				"X-User-Email": {"alice@example.com"},
+			},
+			want: http.Header{
+				"X-User-Email": {"alice@example.com"},
+				"X-User-Id":    {"i42a3Eh1wNyoFuPhe3rIe0XkCUW3MfoC47Qr8QFYniE="},
+			},
+		},
+		{
+			name: "missing header",
+			rule: types.Rule{
+				Header: "X-User-Email",
+				Value:  "X-User-Id",
+			},
+			requestHeaders: http.Header{},
+			want:           http.Header{},
+		},
+		{
+			name: "response header with several values",
+			rule: types.Rule{
+				Header:        "X-Values",
+				Algorithm:     types.SHA512,
+				Encoding:      types.EncodingBase64URL,
+				SetOnResponse: true,
+			},
+			responseHeaders: http.Header{
+				"X-Values": {"a", "b"},
+			},
+			want: http.Header{
+				"X-Values": {"PZVvUiZjprMo5FHjcMJvW1JaZ1_IG2uZZX7AY4ekdxwlaJ_PuAIOCe35Evq0gH0J42gRNCBSwjPQbTmAO3NSwQ"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			headers := req.Header
+			if test.rule.SetOnResponse {
+				headers = rw.Header()
+			}
+
+			for name, values := range test.requestHeaders {
+				req.Header[name] = values
+			}
+
+			for name, values := range test.responseHeaders {
+				rw.Header()[name] = values
+			}
+
+			hashHandler, err := hasher.New(test.rule)
+			require.NoError(t, err)
+
+			hashHandler.Handle(rw, req)
+
+			assert.Equal(t, test.want, headers)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: true,
+		},
+		{
+			name: "invalid algorithm",
+			rule: types.Rule{
+				Header:    "X-User-Email",
+				Algorithm: "MD5",
+				Type:      types.Hash,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid encoding",
+			rule: types.Rule{
+				Header:   "X-User-Email",
+				Encoding: "Base32",
+				Type:     types.Hash,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:    "X-User-Email",
+				Value:     "X-User-Id",
+				Algorithm: types.SHA512,
+				Encoding:  types.EncodingBase64,
+				Salt:      "pepper",
+				Type:      types.Hash,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			hashHandler, err := hasher.New(test.rule)
+			require.NoError(t, err)
+
+			err = hashHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package headertourl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/template"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/query"
+)
+
+// HeaderToURL writes a header into a query parameter or the path of the request URL.
+type HeaderToURL struct {
+	rule *types.Rule
+	path *template.Template
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	path, err := template.Parse(rule.Value)
+	if err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+
+	return &HeaderToURL{rule: &rule, path: path}, nil
+}
+
+func (h *HeaderToURL) Validate() error {
+	if h.rule.Header == "" || (h.rule.Query == "" && h.rule.Value == "") {
+		return types.ErrMissingRequiredFields
+	}
+
+	if h.rule.Query != "" && h.rule.Value != "" {
+		return fmt.Errorf("%w: only one of Query and Value can be set", types.ErrInvalidOption)
+	}
+
+	if h.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (h *HeaderToURL) Handle(_ http.ResponseWriter, req *http.Request) {
+	values := header.Values(req, h.rule.Header)
+	if len(values) == 0 {
+		return
+	}
+
+	if h.rule.Query != "" {
+		req.URL.RawQuery = query.Set(req.URL.RawQuery, h.rule.Query, values...)
+	} else if !validSegments(values) || !h.setPath(req) {
+		return
+	}
+
+	if h.rule.RemoveSource {
+		header.Delete(req, h.rule.Header)
+	}
+}
+
+// validSegments reports whether the header values can be written as a path segment,
+// without adding segments or moving up the path.
+func validSegments(values []string) bool {
+	for _, value := range values {
+		if value == "." || value == ".." || strings.Contains(value, "/") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setPath replaces the request path by the escaped path template, it returns
+// false if the path is left unchanged because the template result is invalid
+// or has dot segments.
+func (h *HeaderToURL) setPath(req *http.Request) bool {
+	escaped := h.path.Execute(req)
+	if !strings.HasPrefix(escaped, "/") {
+		escaped = "/" + escaped
+	}
+
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		return false
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	req.URL.Path = path
+	req.URL.RawPath = escaped
+
+	return true
+}
@@ -0,0 +1,269 @@
+package headertourl_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/headertourl"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestHeaderToURLHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		rule           types.Rule
+		url            string
+		requestHeaders []string
+		wantHeader     []string
+		wantURL        string
+		wantPath       string
+	}{
+		{
+			name: "copy to query parameter",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+			},
+			url:            "http://example.com/foo?page=2",
+			requestHeaders: []string{"acme & co"},
+			wantHeader:     []string{"acme & co"},
+			wantURL:        "http://example.com/foo?page=2&tenant=acme+%26+co",
+			wantPath:       "/foo",
+		},
+		{
+			name: "move to query parameter",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Query:        "tenant",
+				RemoveSource: true,
+			},
+			url:            "http://example.com/foo?tenant=other&page=2",
+			requestHeaders: []string{"acme", "corp"},
+			wantHeader:     nil,
+			wantURL:        "http://example.com/foo?page=2&tenant=acme&tenant=corp",
+			wantPath:       "/foo",
+		},
+		{
+			name: "missing header",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Query:        "tenant",
+				RemoveSource: true,
+			},
+			url:      "http://example.com/foo?tenant=other",
+			wantURL:  "http://example.com/foo?tenant=other",
+			wantPath: "/foo",
+		},
+		{
+			name: "path template",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  `/tenants/{{ .Header "X-Tenant" | pathescape }}{{ .EscapedPath }}`,
+			},
+			url:            "http://example.com/users?page=2",
+			requestHeaders: []string{"acme corp"},
+			wantHeader:     []string{"acme corp"},
+			wantURL:        "http://example.com/tenants/acme%20corp/users?page=2",
+			wantPath:       "/tenants/acme corp/users",
+		},
+		{
+			name: "path template keeping an escaped slash",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  `/tenants/{{ .Header "X-Tenant" | pathescape }}{{ .EscapedPath }}`,
+			},
+			url:            "http://example.com/a%2Fb",
+			requestHeaders: []string{"acme"},
+			wantHeader:     []string{"acme"},
+			wantURL:        "http://example.com/tenants/acme/a%2Fb",
+			wantPath:       "/tenants/acme/a/b",
+		},
+		{
+			name: "path template keeping an escaped percent",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  `/tenants/{{ .Header "X-Tenant" | pathescape }}{{ .EscapedPath }}`,
+			},
+			url:            "http://example.com/files/100%25",
+			requestHeaders: []string{"acme"},
+			wantHeader:     []string{"acme"},
+			wantURL:        "http://example.com/tenants/acme/files/100%25",
+			wantPath:       "/tenants/acme/files/100%",
+		},
+		{
+			name: "dot segment header",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Value:        `/tenants/{{ .Header "X-Tenant" | pathescape }}{{ .EscapedPath }}`,
+				RemoveSource: true,
+			},
+			url:            "http://example.com/users",
+			requestHeaders: []string{".."},
+			wantHeader:     []string{".."},
+			wantURL:        "http://example.com/users",
+			wantPath:       "/users",
+		},
+		{
+			name: "header with a slash",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  `/tenants/{{ .Header "X-Tenant" }}{{ .EscapedPath }}`,
+			},
+			url:            "http://example.com/users",
+			requestHeaders: []string{"acme/../admin"},
+			wantHeader:     []string{"acme/../admin"},
+			wantURL:        "http://example.com/users",
+			wantPath:       "/users",
+		},
+		{
+			name: "dot segment in the path template result",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  `/tenants/{{ .Header "X-Tenant" }}/{{ .Query "page" }}`,
+			},
+			url:            "http://example.com/users?page=%2E%2E",
+			requestHeaders: []string{"acme"},
+			wantHeader:     []string{"acme"},
+			wantURL:        "http://example.com/users?page=%2E%2E",
+			wantPath:       "/users",
+		},
+		{
+			name: "path template without leading slash",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Value:        `{{ .Header "X-Tenant" }}{{ .Path }}`,
+				RemoveSource: true,
+			},
+			url:            "http://example.com/users",
+			requestHeaders: []string{"acme"},
+			wantHeader:     nil,
+			wantURL:        "http://example.com/acme/users",
+			wantPath:       "/acme/users",
+		},
+		{
+			name: "invalid path template result",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Value:        `/{{ .Header "X-Tenant" }}`,
+				RemoveSource: true,
+			},
+			url:            "http://example.com/users",
+			requestHeaders: []string{"100%"},
+			wantHeader:     []string{"100%"},
+			wantURL:        "http://example.com/users",
+			wantPath:       "/users",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+
+			for _, value := range test.requestHeaders {
+				req.Header.Add(test.rule.Header, value)
+			}
+
+			headerToURLHandler, err := headertourl.New(test.rule)
+			require.NoError(t, err)
+
+			headerToURLHandler.Handle(nil, req)
+
+			assert.Equal(t, test.wantHeader, req.Header.Values(test.rule.Header))
+			assert.Equal(t, test.wantURL, req.URL.String())
+			assert.Equal(t, test.wantPath, req.URL.Path)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "missing target",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Type:   types.HeaderToURL,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "several targets",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+				Value:  "/{{ .Path }}",
+				Type:   types.HeaderToURL,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid template",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Value:  "{{ .Foo }}",
+				Type:   types.HeaderToURL,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Tenant",
+				Query:         "tenant",
+				Type:          types.HeaderToURL,
+				SetOnResponse: true,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+				Type:   types.HeaderToURL,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			headerToURLHandler, err := headertourl.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = headerToURLHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,18 +1,27 @@
 package join
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/tomMoulard/htransformation/pkg/template"
 	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
 )
 
 type Join struct {
-	rule *types.Rule
+	rule   *types.Rule
+	values []*template.Template
 }
 
 func New(rule types.Rule) (types.Handler, error) {
-	return &Join{rule: &rule}, nil
+	values, err := template.ParseAll(rule.Values)
+	if err != nil {
+		return nil, fmt.Errorf("values: %w", err)
+	}
+
+	return &Join{rule: &rule, values: values}, nil
 }
 
 func (j *Join) Validate() error {
@@ -37,8 +46,8 @@ func (j *Join) Handle(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	newHeaderVal := val[0]
-	for _, value := range j.rule.Values {
-		newHeaderVal += j.rule.Sep + getValue(value, j.rule.HeaderPrefix, req)
+	for _, value := range j.values {
+		newHeaderVal += j.rule.Sep + header.Resolve(req, value.Execute(req), j.rule.HeaderPrefix)
 	}
 
 	if j.rule.SetOnResponse {
@@ -53,29 +62,3 @@ func (j *Join) Handle(rw http.ResponseWriter, req *http.Request) {
 		req.Header.Set(j.rule.Header, newHeaderVal)
 	}
 }
-
-// getValue checks if prefix exists, the given prefix is present,
-// and then proceeds to read the existing header (after stripping the prefix)
-// to return as value.
-func getValue(ruleValue, valueIsHeaderPrefix string, req *http.Request) string {
-	actualValue := ruleValue
-
-	if valueIsHeaderPrefix != "" && strings.HasPrefix(ruleValue, valueIsHeaderPrefix) {
-		header := strings.TrimPrefix(ruleValue, valueIsHeaderPrefix)
-		// If the resulting value after removing the prefix is empty,
-		// we return the actual value,
-		// which is the prefix itself.
-		// This is because doing a req.Header.Get("") would not fly well.
-		if header == "" {
-			return actualValue
-		}
-
-		if strings.EqualFold(header, "Host") {
-			actualValue = req.Host
-		} else {
-			actualValue = req.Header.Get(header)
-		}
-	}
-
-	return actualValue
-}
@@ -174,6 +174,24 @@ func TestJoinHandler(t *testing.T) {
 			},
 			expectedHost: "example.com,Tested",
 		},
+		{
+			name: "Join template value",
+			rule: types.Rule{
+				Sep:    ",",
+				Header: "X-Test",
+				Values: []string{
+					`{{ .Header "X-Source" | upper }}`,
+				},
+			},
+			requestHeaders: map[string]string{
+				"X-Source": "Tested",
+				"X-Test":   "Bar",
+			},
+			expectedHeaders: map[string]string{
+				"X-Test": "Bar,TESTED",
+			},
+			expectedHost: "example.com",
+		},
 		{
 			name: "Twice Host header",
 			rule: types.Rule{
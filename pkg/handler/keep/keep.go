@@ -0,0 +1,86 @@
+package keep
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+type Keep struct {
+	rule    *types.Rule
+	allowed []*regexp.Regexp
+}
+
+// protectedHeaders returns the headers kept even if they are not listed, unless DropProtected is set.
+func protectedHeaders() []string {
+	return []string{"Host", "Content-Length", "Transfer-Encoding"}
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	allowed := make([]*regexp.Regexp, 0, len(rule.Values))
+
+	for _, value := range rule.Values {
+		re, err := header.NameRegexp(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+		}
+
+		allowed = append(allowed, re)
+	}
+
+	return &Keep{rule: &rule, allowed: allowed}, nil
+}
+
+func (k *Keep) Validate() error {
+	if len(k.rule.Values) == 0 {
+		return types.ErrMissingRequiredFields
+	}
+
+	return nil
+}
+
+func (k *Keep) Handle(rw http.ResponseWriter, req *http.Request) {
+	if k.rule.SetOnResponse {
+		for name := range rw.Header() {
+			if !k.isKept(name) {
+				rw.Header().Del(name)
+			}
+		}
+
+		return
+	}
+
+	for name := range req.Header {
+		if !k.isKept(name) {
+			req.Header.Del(name)
+		}
+	}
+
+	if !k.isKept("Host") {
+		header.Delete(req, "Host")
+	}
+}
+
+// isKept reports whether the header is listed or protected.
+func (k *Keep) isKept(name string) bool {
+	for _, re := range k.allowed {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	if k.rule.DropProtected {
+		return false
+	}
+
+	for _, protected := range protectedHeaders() {
+		if http.CanonicalHeaderKey(name) == protected {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,280 @@
+package keep_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/keep"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestKeepHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		requestHeaders  map[string]string
+		expectedHeaders map[string]string
+		expectedHost    string
+	}{
+		{
+			name: "keep listed headers",
+			rule: types.Rule{
+				Values: []string{"X-Keep", "accept"},
+			},
+			requestHeaders: map[string]string{
+				"X-Keep":   "foo",
+				"Accept":   "text/html",
+				"X-Delete": "bar",
+			},
+			expectedHeaders: map[string]string{
+				"X-Keep":   "foo",
+				"Accept":   "text/html",
+				"X-Delete": "",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "keep headers matching a regexp",
+			rule: types.Rule{
+				Values: []string{"X-Keep-.*"},
+			},
+			requestHeaders: map[string]string{
+				"X-Keep-A":         "foo",
+				"X-Keep-B":         "bar",
+				"X-Not-X-Keep-C":   "baz",
+				"X-Keep-Long-Name": "qux",
+			},
+			expectedHeaders: map[string]string{
+				"X-Keep-A":         "foo",
+				"X-Keep-B":         "bar",
+				"X-Not-X-Keep-C":   "",
+				"X-Keep-Long-Name": "qux",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "names are matched as a whole",
+			rule: types.Rule{
+				Values: []string{"X-Keep"},
+			},
+			requestHeaders: map[string]string{
+				"X-Keep":       "foo",
+				"X-Keep-Not":   "bar",
+				"X-Not-X-Keep": "baz",
+			},
+			expectedHeaders: map[string]string{
+				"X-Keep":       "foo",
+				"X-Keep-Not":   "",
+				"X-Not-X-Keep": "",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "protected headers are kept",
+			rule: types.Rule{
+				Values: []string{"X-Keep"},
+			},
+			requestHeaders: map[string]string{
+				"Content-Length":    "3",
+				"Transfer-Encoding": "chunked",
+			},
+			expectedHeaders: map[string]string{
+				"Content-Length":    "3",
+				"Transfer-Encoding": "chunked",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "drop protected headers",
+			rule: types.Rule{
+				Values:        []string{"X-Keep"},
+				DropProtected: true,
+			},
+			requestHeaders: map[string]string{
+				"X-Keep":            "foo",
+				"Content-Length":    "3",
+				"Transfer-Encoding": "chunked",
+			},
+			expectedHeaders: map[string]string{
+				"X-Keep":            "foo",
+				"Content-Length":    "",
+				"Transfer-Encoding": "",
+			},
+			expectedHost: "",
+		},
+		{
+			name: "drop protected headers not listed",
+			rule: types.Rule{
+				Values:        []string{"Host", "Content-Length"},
+				DropProtected: true,
+			},
+			requestHeaders: map[string]string{
+				"Content-Length":    "3",
+				"Transfer-Encoding": "chunked",
+			},
+			expectedHeaders: map[string]string{
+				"Content-Length":    "3",
+				"Transfer-Encoding": "",
+			},
+			expectedHost: "example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for hName, hVal := range test.requestHeaders {
+				req.Header.Add(hName, hVal)
+			}
+
+			keepHandler, err := keep.New(test.rule)
+			require.NoError(t, err)
+
+			keepHandler.Handle(nil, req)
+
+			for hName, hVal := range test.expectedHeaders {
+				assert.Equal(t, hVal, req.Header.Get(hName))
+			}
+
+			assert.Equal(t, test.expectedHost, req.Host)
+		})
+	}
+}
+
+func TestKeepHandlerOnResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		responseHeaders map[string]string
+		want            map[string]string
+	}{
+		{
+			name: "keep listed headers",
+			rule: types.Rule{
+				Values:        []string{"Content-Type", "X-Keep-.*"},
+				SetOnResponse: true,
+			},
+			responseHeaders: map[string]string{
+				"Content-Type":   "text/html",
+				"X-Keep-A":       "foo",
+				"Server":         "nginx",
+				"Content-Length": "3",
+			},
+			want: map[string]string{
+				"Content-Type":   "text/html",
+				"X-Keep-A":       "foo",
+				"Server":         "",
+				"Content-Length": "3",
+			},
+		},
+		{
+			name: "drop protected headers",
+			rule: types.Rule{
+				Values:        []string{"Content-Type"},
+				SetOnResponse: true,
+				DropProtected: true,
+			},
+			responseHeaders: map[string]string{
+				"Content-Type":   "text/html",
+				"Content-Length": "3",
+			},
+			want: map[string]string{
+				"Content-Type":   "text/html",
+				"Content-Length": "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			for hName, hVal := range test.responseHeaders {
+				rw.Header().Add(hName, hVal)
+			}
+
+			keepHandler, err := keep.New(test.rule)
+			require.NoError(t, err)
+
+			keepHandler.Handle(rw, req)
+
+			for hName, hVal := range test.want {
+				assert.Equal(t, hVal, rw.Header().Get(hName))
+			}
+
+			assert.Equal(t, "example.com", req.Host)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Values: []string{"("},
+				Type:   types.Keep,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Values: []string{"X-Keep"},
+				Type:   types.Keep,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			keepHandler, err := keep.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = keepHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package reject
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+)
+
+// Reject answers the request with an error, without forwarding it.
+type Reject struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.StatusCode == 0 {
+		rule.StatusCode = http.StatusBadRequest
+	}
+
+	return &Reject{rule: &rule}, nil
+}
+
+func (r *Reject) Validate() error {
+	if r.rule.StatusCode < http.StatusOK || r.rule.StatusCode > 599 {
+		return fmt.Errorf("%w: StatusCode %d", types.ErrInvalidOption, r.rule.StatusCode)
+	}
+
+	if r.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (r *Reject) Handle(rw http.ResponseWriter, _ *http.Request) {
+	for name, value := range r.rule.ResponseHeaders {
+		rw.Header().Set(name, value)
+	}
+
+	response.Write(rw, r.rule.StatusCode, r.rule.Body)
+}
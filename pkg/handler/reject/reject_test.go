@@ -0,0 +1,144 @@
+package reject_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/reject"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestRejectHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		wantStatus  int
+		wantBody    string
+		wantHeaders http.Header
+	}{
+		{
+			name:        "default status",
+			wantStatus:  http.StatusBadRequest,
+			wantHeaders: http.Header{},
+		},
+		{
+			name: "status, body and headers",
+			rule: types.Rule{
+				StatusCode:      http.StatusRequestHeaderFieldsTooLarge,
+				Body:            "header too large",
+				ResponseHeaders: map[string]string{"X-Reason": "too-large"},
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+			wantBody:   "header too large",
+			wantHeaders: http.Header{
+				"Content-Type": {"text/plain; charset=utf-8"},
+				"X-Reason":     {"too-large"},
+			},
+		},
+		{
+			name: "JSON body",
+			rule: types.Rule{
+				StatusCode:      http.StatusForbidden,
+				Body:            `{"error":"forbidden"}`,
+				ResponseHeaders: map[string]string{"Content-Type": "application/json"},
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `{"error":"forbidden"}`,
+			wantHeaders: http.Header{
+				"Content-Type": {"application/json"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rejectHandler, err := reject.New(test.rule)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			rejectHandler.Handle(rw, req)
+
+			assert.Equal(t, test.wantStatus, rw.Code)
+			assert.Equal(t, test.wantBody, rw.Body.String())
+			assert.Equal(t, test.wantHeaders, rw.Header())
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: false,
+		},
+		{
+			name: "invalid status code",
+			rule: types.Rule{
+				StatusCode: 42,
+				Type:       types.Reject,
+			},
+			wantErr: true,
+		},
+		{
+			name: "informational status code",
+			rule: types.Rule{
+				StatusCode: http.StatusEarlyHints,
+				Type:       types.Reject,
+			},
+			wantErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Type:          types.Reject,
+				SetOnResponse: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				StatusCode:      http.StatusForbidden,
+				Body:            "forbidden",
+				ResponseHeaders: map[string]string{"X-Reason": "policy"},
+				Type:            types.Reject,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rejectHandler, err := reject.New(test.rule)
+			require.NoError(t, err)
+
+			err = rejectHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
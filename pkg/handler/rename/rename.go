@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/tomMoulard/htransformation/pkg/types"
 	"github.com/tomMoulard/htransformation/pkg/utils/header"
@@ -13,6 +16,16 @@ type Rename struct {
 	rule *types.Rule
 }
 
+// groupReference matches the capture group references of a target, as expanded by regexp.Expand.
+var groupReference = regexp.MustCompile(`\$(?:\$|\{(\w+)\}|(\w+))`)
+
+// renaming is a header to rename.
+type renaming struct {
+	from   string
+	to     string
+	values []string
+}
+
 func New(rule types.Rule) (types.Handler, error) {
 	re, err := regexp.Compile(rule.Header)
 	if err != nil {
@@ -21,6 +34,10 @@ func New(rule types.Rule) (types.Handler, error) {
 
 	rule.Regexp = re
 
+	if rule.OnConflict == "" {
+		rule.OnConflict = types.ConflictOverwrite
+	}
+
 	return &Rename{rule: &rule}, nil
 }
 
@@ -29,32 +46,162 @@ func (r *Rename) Validate() error {
 		return types.ErrMissingRequiredFields
 	}
 
+	switch r.rule.OnConflict {
+	case types.ConflictOverwrite, types.ConflictAppend, types.ConflictKeepFirst, types.ConflictError:
+	default:
+		return fmt.Errorf("%w: OnConflict %q", types.ErrInvalidOption, r.rule.OnConflict)
+	}
+
+	if r.rule.HeaderPrefix != "" && strings.HasPrefix(r.rule.Value, r.rule.HeaderPrefix) {
+		// the target is read from a header, it is not known yet.
+		return nil
+	}
+
+	return r.validateReferences()
+}
+
+// validateReferences checks that the capture groups referenced by the target exist in the Header regex,
+// a missing group being expanded to an empty string.
+func (r *Rename) validateReferences() error {
+	for _, match := range groupReference.FindAllStringSubmatch(r.rule.Value, -1) {
+		name := match[1] + match[2]
+		if name == "" {
+			// escaped dollar sign.
+			continue
+		}
+
+		if index, err := strconv.Atoi(name); err == nil {
+			if index > r.rule.Regexp.NumSubexp() {
+				return fmt.Errorf("%w: Value references the missing capture group %d", types.ErrInvalidOption, index)
+			}
+
+			continue
+		}
+
+		if r.rule.Regexp.SubexpIndex(name) < 0 {
+			return fmt.Errorf("%w: Value references the missing capture group %q", types.ErrInvalidOption, name)
+		}
+	}
+
 	return nil
 }
 
 func (r *Rename) Handle(rw http.ResponseWriter, req *http.Request) {
-	originalHost := req.Header.Get("Host") // Eventually X-Forwarded-Host
-	req.Header.Set("Host", req.Host)
+	target := header.Resolve(req, r.rule.Value, r.rule.HeaderPrefix)
+	if target == "" && r.rule.Value != "" {
+		// the target header name is read from a missing header.
+		return
+	}
 
-	for headerName, headerValues := range req.Header {
-		if matched := r.rule.Regexp.Match([]byte(headerName)); !matched {
+	renamings := r.renamings(r.headers(req), target)
+
+	targets, ok := r.resolveConflicts(renamings)
+	if !ok {
+		return
+	}
+
+	for _, source := range renamings {
+		if r.rule.SetOnResponse {
+			rw.Header().Del(source.from)
+		} else {
+			header.Delete(req, source.from)
+		}
+	}
+
+	for _, renamed := range targets {
+		if renamed.to == "" {
 			continue
 		}
 
 		if r.rule.SetOnResponse {
-			rw.Header().Del(headerName)
+			rw.Header().Del(renamed.to)
 		} else {
-			header.Delete(req, headerName)
+			header.Delete(req, renamed.to)
 		}
 
-		for _, val := range headerValues {
+		for _, val := range renamed.values {
 			if r.rule.SetOnResponse {
-				rw.Header().Set(r.rule.Value, val)
+				rw.Header().Add(renamed.to, val)
 			} else {
-				header.Set(req, r.rule.Value, val)
+				header.Add(req, renamed.to, val)
 			}
 		}
 	}
+}
+
+// headers returns the request headers the rule applies to, including the Host.
+// The request headers are read even when the renamed headers are set on the response.
+func (r *Rename) headers(req *http.Request) http.Header {
+	headers := make(http.Header, len(req.Header)+1)
+	for name, values := range req.Header {
+		headers[name] = values
+	}
+
+	if req.Host != "" {
+		headers["Host"] = []string{req.Host}
+	}
+
+	return headers
+}
+
+// renamings returns the headers matching the rule sorted by name, along with
+// their new name where the target capture groups are expanded.
+func (r *Rename) renamings(headers http.Header, target string) []renaming {
+	renamings := make([]renaming, 0, len(headers))
+
+	for name, values := range headers {
+		match := r.rule.Regexp.FindStringSubmatchIndex(name)
+		if match == nil {
+			continue
+		}
+
+		newName := target
+		if strings.Contains(target, "$") {
+			newName = string(r.rule.Regexp.ExpandString(nil, target, name, match))
+			if newName == "" {
+				// the header is kept rather than deleted, as its capture groups are empty.
+				continue
+			}
+		}
+
+		renamings = append(renamings, renaming{from: name, to: newName, values: values})
+	}
+
+	sort.Slice(renamings, func(i, j int) bool {
+		return renamings[i].from < renamings[j].from
+	})
+
+	return renamings
+}
+
+// resolveConflicts merges the renamings sharing the same new name according to
+// the conflict policy. It returns false if the rule must not be applied.
+func (r *Rename) resolveConflicts(renamings []renaming) ([]renaming, bool) {
+	targets := make([]renaming, 0, len(renamings))
+	indexes := make(map[string]int, len(renamings))
+
+	for _, current := range renamings {
+		key := http.CanonicalHeaderKey(current.to)
+
+		index, conflict := indexes[key]
+		if !conflict {
+			indexes[key] = len(targets)
+			targets = append(targets, current)
+
+			continue
+		}
+
+		switch r.rule.OnConflict {
+		case types.ConflictOverwrite:
+			targets[index].values = current.values
+		case types.ConflictAppend:
+			values := make([]string, 0, len(targets[index].values)+len(current.values))
+			targets[index].values = append(append(values, targets[index].values...), current.values...)
+		case types.ConflictKeepFirst:
+		case types.ConflictError:
+			return nil, false
+		}
+	}
 
-	req.Header.Set("Host", originalHost)
+	return targets, true
 }
@@ -1,7 +1,10 @@
 package rename_test
 
 import (
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/tomMoulard/htransformation/pkg/handler/rename"
@@ -64,6 +67,38 @@ func TestRenameHandler(t *testing.T) {
 			},
 			expectedHost: "example.com",
 		},
+		{
+			name: "rename to a name read from another header",
+			rule: types.Rule{
+				Header:       "Test",
+				Value:        "^X-Target",
+				HeaderPrefix: "^",
+			},
+			requestHeaders: map[string]string{
+				"Test":     "Success",
+				"X-Target": "X-Testing",
+			},
+			expectedHeaders: map[string]string{
+				"Test":      "",
+				"X-Testing": "Success",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "rename to a name read from a missing header",
+			rule: types.Rule{
+				Header:       "Test",
+				Value:        "^X-Target",
+				HeaderPrefix: "^",
+			},
+			requestHeaders: map[string]string{
+				"Test": "Success",
+			},
+			expectedHeaders: map[string]string{
+				"Test": "Success",
+			},
+			expectedHost: "example.com",
+		},
 		{
 			name: "Rename Host to another",
 			rule: types.Rule{
@@ -125,6 +160,214 @@ func TestRenameHandler(t *testing.T) {
 	}
 }
 
+func TestRenameCaptureGroupsAndConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		rule            types.Rule
+		headers         map[string][]string
+		expectedHeaders map[string][]string
+	}{
+		{
+			name: "capture group",
+			rule: types.Rule{
+				Header: "^X-Traefik-(.*)$",
+				Value:  "X-Custom-$1",
+			},
+			headers: map[string][]string{
+				"X-Traefik-Uuid": {"0"},
+				"X-Traefik-Date": {"today"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-Uuid": nil,
+				"X-Traefik-Date": nil,
+				"X-Custom-Uuid":  {"0"},
+				"X-Custom-Date":  {"today"},
+			},
+		},
+		{
+			name: "named capture group",
+			rule: types.Rule{
+				Header: "^X-(?P<kind>[^-]+)-Id$",
+				Value:  "X-Id-${kind}",
+			},
+			headers: map[string][]string{
+				"X-Request-Id": {"1234"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Request-Id": nil,
+				"X-Id-Request": {"1234"},
+			},
+		},
+		{
+			name: "multi-valued header",
+			rule: types.Rule{
+				Header: "^X-Source$",
+				Value:  "X-Target",
+			},
+			headers: map[string][]string{
+				"X-Source": {"foo", "bar"},
+				"X-Target": {"baz"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Source": nil,
+				"X-Target": {"foo", "bar"},
+			},
+		},
+		{
+			name: "empty capture group",
+			rule: types.Rule{
+				Header: "^X-Traefik-(.*)$",
+				Value:  "$1",
+			},
+			headers: map[string][]string{
+				"X-Traefik-":    {"kept"},
+				"X-Traefik-Foo": {"renamed"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-":    {"kept"},
+				"X-Traefik-Foo": nil,
+				"Foo":           {"renamed"},
+			},
+		},
+		{
+			name: "conflict overwrite by default",
+			rule: types.Rule{
+				Header: "^X-Traefik-",
+				Value:  "X-Traefik-Merged",
+			},
+			headers: map[string][]string{
+				"X-Traefik-B": {"b"},
+				"X-Traefik-A": {"a1", "a2"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-A":      nil,
+				"X-Traefik-B":      nil,
+				"X-Traefik-Merged": {"b"},
+			},
+		},
+		{
+			name: "conflict append",
+			rule: types.Rule{
+				Header:     "^X-Traefik-",
+				Value:      "X-Traefik-Merged",
+				OnConflict: types.ConflictAppend,
+			},
+			headers: map[string][]string{
+				"X-Traefik-B": {"b"},
+				"X-Traefik-A": {"a1", "a2"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-A":      nil,
+				"X-Traefik-B":      nil,
+				"X-Traefik-Merged": {"a1", "a2", "b"},
+			},
+		},
+		{
+			name: "conflict keep first",
+			rule: types.Rule{
+				Header:     "^X-Traefik-",
+				Value:      "X-Traefik-Merged",
+				OnConflict: types.ConflictKeepFirst,
+			},
+			headers: map[string][]string{
+				"X-Traefik-B": {"b"},
+				"X-Traefik-A": {"a1", "a2"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-A":      nil,
+				"X-Traefik-B":      nil,
+				"X-Traefik-Merged": {"a1", "a2"},
+			},
+		},
+		{
+			name: "conflict error",
+			rule: types.Rule{
+				Header:     "^X-Traefik-",
+				Value:      "X-Traefik-Merged",
+				OnConflict: types.ConflictError,
+			},
+			headers: map[string][]string{
+				"X-Traefik-B": {"b"},
+				"X-Traefik-A": {"a"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-A":      {"a"},
+				"X-Traefik-B":      {"b"},
+				"X-Traefik-Merged": nil,
+			},
+		},
+		{
+			name: "no conflict error",
+			rule: types.Rule{
+				Header:     "^X-Traefik-(.*)$",
+				Value:      "X-Custom-$1",
+				OnConflict: types.ConflictError,
+			},
+			headers: map[string][]string{
+				"X-Traefik-B": {"b"},
+				"X-Traefik-A": {"a"},
+			},
+			expectedHeaders: map[string][]string{
+				"X-Traefik-A": nil,
+				"X-Traefik-B": nil,
+				"X-Custom-A":  {"a"},
+				"X-Custom-B":  {"b"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for _, onResponse := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s on response %t", test.name, onResponse), func(t *testing.T) {
+				t.Parallel()
+
+				req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+				require.NoError(t, err)
+
+				rw := httptest.NewRecorder()
+
+				// the renamed request headers are set on the response when the rule is applied on it.
+				for hName, hVals := range test.headers {
+					for _, hVal := range hVals {
+						req.Header.Add(hName, hVal)
+					}
+				}
+
+				headers := req.Header
+				if onResponse {
+					headers = rw.Header()
+				}
+
+				rule := test.rule
+				rule.SetOnResponse = onResponse
+
+				renameHandler, err := rename.New(rule)
+				require.NoError(t, err)
+				require.NoError(t, renameHandler.Validate())
+
+				renameHandler.Handle(rw, req)
+
+				for hName, hVals := range test.expectedHeaders {
+					// the request headers left unchanged are not set on the response.
+					if onResponse && strings.Join(hVals, ",") == strings.Join(test.headers[hName], ",") {
+						hVals = nil
+					}
+
+					assert.Equalf(t, hVals, headers.Values(hName), "header %q", hName)
+				}
+
+				if onResponse {
+					assert.Equal(t, http.Header(test.headers), req.Header)
+				}
+
+				assert.Equal(t, "example.com", req.Host)
+			})
+		}
+	}
+}
+
 func TestValidation(t *testing.T) {
 	t.Parallel()
 
@@ -154,6 +397,43 @@ func TestValidation(t *testing.T) {
 			},
 			wantNewErr: true,
 		},
+		{
+			name: "invalid conflict policy",
+			rule: types.Rule{
+				Header:     "not-empty",
+				Value:      "not-empty",
+				Type:       types.Rename,
+				OnConflict: "not-a-policy",
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "missing capture group",
+			rule: types.Rule{
+				Header: "^X-(.*)$",
+				Value:  "$2",
+				Type:   types.Rename,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "missing named capture group",
+			rule: types.Rule{
+				Header: "^X-(?P<name>.*)$",
+				Value:  "X-${nme}",
+				Type:   types.Rename,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid capture groups",
+			rule: types.Rule{
+				Header: "^X-(?P<name>.*)-(.*)$",
+				Value:  "X-${name}-$2-$$",
+				Type:   types.Rename,
+			},
+			wantValidateErr: false,
+		},
 		{
 			name: "valid rule",
 			rule: types.Rule{
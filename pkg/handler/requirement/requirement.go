@@ -0,0 +1,94 @@
+package requirement
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+)
+
+// Require checks that a request header is present and that each of its values matches the Value regexp, or is one of
+// the Values.
+type Require struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Value != "" {
+		// values are matched as a whole.
+		re, err := regexp.Compile("^(?:" + rule.Value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+		}
+
+		rule.Regexp = re
+	}
+
+	return &Require{rule: &rule}, nil
+}
+
+func (r *Require) Validate() error {
+	if r.rule.Header == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	if r.rule.Value != "" && len(r.rule.Values) > 0 {
+		return fmt.Errorf("%w: only one of Value and Values can be set", types.ErrInvalidOption)
+	}
+
+	if r.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+// Handle rejects the request if it does not satisfy the requirement, the plugin checks the requirements of every
+// rule at once instead.
+func (r *Require) Handle(rw http.ResponseWriter, req *http.Request) {
+	if violation := r.Check(req); violation != nil {
+		response.WriteProblem(rw, []types.Violation{*violation})
+	}
+}
+
+func (r *Require) Check(req *http.Request) *types.Violation {
+	values := header.Values(req, r.rule.Header)
+	if len(values) == 0 {
+		return &types.Violation{Name: r.rule.Header, Reason: "missing"}
+	}
+
+	for _, value := range values {
+		if r.rule.Regexp != nil && !r.rule.Regexp.MatchString(value) {
+			// the regexp is not sent back, the configuration being internal.
+			return &types.Violation{Name: r.rule.Header, Reason: "invalid format"}
+		}
+
+		if len(r.rule.Values) > 0 && !r.allowed(value) {
+			return &types.Violation{Name: r.rule.Header, Reason: "not one of " + strings.Join(r.rule.Values, ", ")}
+		}
+	}
+
+	return nil
+}
+
+// allowed reports whether the value is one of the Values. A media type, such as a Content-Type, is compared without
+// its parameters, unless the allowed value has some.
+func (r *Require) allowed(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		mediaType = value
+	}
+
+	for _, allowed := range r.rule.Values {
+		if strings.EqualFold(allowed, value) || strings.EqualFold(allowed, mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
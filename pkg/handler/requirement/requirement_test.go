@@ -0,0 +1,206 @@
+package requirement_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/requirement"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestRequireHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rule          types.Rule
+		headers       http.Header
+		wantViolation *types.Violation
+	}{
+		{
+			name:    "present",
+			rule:    types.Rule{Header: "X-Api-Key"},
+			headers: http.Header{"X-Api-Key": {""}},
+		},
+		{
+			name:          "missing",
+			rule:          types.Rule{Header: "X-Api-Key"},
+			headers:       http.Header{},
+			wantViolation: &types.Violation{Name: "X-Api-Key", Reason: "missing"},
+		},
+		{
+			name:    "matching",
+			rule:    types.Rule{Header: "X-Api-Key", Value: "[0-9a-f]{32}"},
+			headers: http.Header{"X-Api-Key": {"0123456789abcdef0123456789abcdef"}},
+		},
+		{
+			name:          "not matching as a whole",
+			rule:          types.Rule{Header: "X-Api-Key", Value: "[0-9a-f]{32}"},
+			headers:       http.Header{"X-Api-Key": {"0123456789abcdef0123456789abcdef0"}},
+			wantViolation: &types.Violation{Name: "X-Api-Key", Reason: "invalid format"},
+		},
+		{
+			name: "one of the values",
+			rule: types.Rule{
+				Header: "Content-Type",
+				Values: []string{"application/json", "application/xml"},
+			},
+			headers: http.Header{"Content-Type": {"Application/JSON"}},
+		},
+		{
+			name: "one of the media types",
+			rule: types.Rule{
+				Header: "Content-Type",
+				Values: []string{"application/json", "application/xml"},
+			},
+			headers: http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		},
+		{
+			name: "one of the media types with parameters",
+			rule: types.Rule{
+				Header: "Content-Type",
+				Values: []string{"text/plain; charset=utf-8"},
+			},
+			headers: http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		},
+		{
+			name: "not one of the values with parameters",
+			rule: types.Rule{
+				Header: "X-Env",
+				Values: []string{"prod"},
+			},
+			headers: http.Header{"X-Env": {"prod; debug=1"}},
+			wantViolation: &types.Violation{
+				Name:   "X-Env",
+				Reason: "not one of prod",
+			},
+		},
+		{
+			name: "not one of the values",
+			rule: types.Rule{
+				Header: "Content-Type",
+				Values: []string{"application/json", "application/xml"},
+			},
+			headers: http.Header{"Content-Type": {"text/plain"}},
+			wantViolation: &types.Violation{
+				Name:   "Content-Type",
+				Reason: "not one of application/json, application/xml",
+			},
+		},
+		{
+			name:    "Host",
+			rule:    types.Rule{Header: "Host", Value: `.*\.com`},
+			headers: http.Header{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Header = test.headers
+
+			requireHandler, err := requirement.New(test.rule)
+			require.NoError(t, err)
+
+			checker, ok := requireHandler.(types.Requirement)
+			if !ok {
+				t.Fatal("Require is not a requirement")
+			}
+
+			assert.Equal(t, test.wantViolation, checker.Check(req))
+
+			rw := httptest.NewRecorder()
+			requireHandler.Handle(rw, req)
+
+			if test.wantViolation != nil {
+				assert.Equal(t, http.StatusBadRequest, rw.Code)
+				assert.Equal(t, "application/problem+json", rw.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, "", rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Header: "X-Api-Key",
+				Value:  "(",
+				Type:   types.Require,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "both regexp and values",
+			rule: types.Rule{
+				Header: "X-Api-Key",
+				Value:  "[0-9a-f]{32}",
+				Values: []string{"key"},
+				Type:   types.Require,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Api-Key",
+				Type:          types.Require,
+				SetOnResponse: true,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "X-Api-Key",
+				Value:  "[0-9a-f]{32}",
+				Type:   types.Require,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			requireHandler, err := requirement.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = requireHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
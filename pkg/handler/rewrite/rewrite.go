@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/tomMoulard/htransformation/pkg/template"
 	"github.com/tomMoulard/htransformation/pkg/types"
 	"github.com/tomMoulard/htransformation/pkg/utils/header"
 )
@@ -13,6 +14,7 @@ import (
 type Rewrite struct {
 	rule            *types.Rule
 	ruleValueRegexp *regexp.Regexp
+	valueReplace    *template.Template
 }
 
 func New(rule types.Rule) (types.Handler, error) {
@@ -28,9 +30,15 @@ func New(rule types.Rule) (types.Handler, error) {
 		return nil, fmt.Errorf("%w: %s: %q", types.ErrInvalidRegexp, rule.Name, rule.Value)
 	}
 
+	valueReplace, err := template.Parse(rule.ValueReplace)
+	if err != nil {
+		return nil, fmt.Errorf("value replace: %w", err)
+	}
+
 	return &Rewrite{
 		rule:            &rule,
 		ruleValueRegexp: reg,
+		valueReplace:    valueReplace,
 	}, nil
 }
 
@@ -42,14 +50,14 @@ func (r *Rewrite) Validate() error {
 	return nil
 }
 
-func (r *Rewrite) replaceHeaderValue(headerValue string) string {
+func (r *Rewrite) replaceHeaderValue(headerValue, valueReplace string) string {
 	return r.ruleValueRegexp.ReplaceAllStringFunc(headerValue, func(match string) string {
 		captures := r.ruleValueRegexp.FindStringSubmatch(match)
 		if len(captures) == 0 || captures[0] == "" {
 			return match
 		}
 
-		replaced := r.rule.ValueReplace
+		replaced := valueReplace
 
 		for j, capture := range captures[1:] {
 			replaced = strings.ReplaceAll(replaced, fmt.Sprintf("$%d", j+1), capture)
@@ -65,6 +73,8 @@ func (r *Rewrite) Handle(rw http.ResponseWriter, req *http.Request) {
 		headers = rw.Header()
 	}
 
+	valueReplace := header.Resolve(req, r.valueReplace.Execute(req), r.rule.HeaderPrefix)
+
 	originalHost := req.Header.Get("Host") // Eventually X-Forwarded-Host
 	req.Header.Set("Host", req.Host)
 
@@ -80,7 +90,7 @@ func (r *Rewrite) Handle(rw http.ResponseWriter, req *http.Request) {
 		}
 
 		for _, headerValue := range headerValues {
-			replacedValue := r.replaceHeaderValue(headerValue)
+			replacedValue := r.replaceHeaderValue(headerValue, valueReplace)
 			if r.rule.SetOnResponse {
 				rw.Header().Add(headerName, replacedValue)
 			} else {
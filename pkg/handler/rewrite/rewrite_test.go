@@ -123,6 +123,39 @@ func TestRewriteHandler(t *testing.T) {
 			},
 			expectedHost: "example.com",
 		},
+		{
+			name: "template replacement",
+			rule: types.Rule{
+				Header:       "Foo",
+				Value:        `X-(\d+)`,
+				ValueReplace: `{{ .Header "Bar" }}-$1`,
+			},
+			requestHeaders: map[string]string{
+				"Bar": "Y",
+				"Foo": "X-12",
+			},
+			expectedHeaders: map[string]string{
+				"Foo": "Y-12",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "replacement read from another header",
+			rule: types.Rule{
+				Header:       "Foo",
+				Value:        `X-(\d+)`,
+				ValueReplace: "^Bar",
+				HeaderPrefix: "^",
+			},
+			requestHeaders: map[string]string{
+				"Bar": "Y-Test",
+				"Foo": "X-12",
+			},
+			expectedHeaders: map[string]string{
+				"Foo": "Y-Test",
+			},
+			expectedHost: "example.com",
+		},
 		{
 			name: "multiple replacements with spaces",
 			rule: types.Rule{
@@ -1,18 +1,50 @@
 package set
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/tomMoulard/htransformation/pkg/template"
 	"github.com/tomMoulard/htransformation/pkg/types"
 	"github.com/tomMoulard/htransformation/pkg/utils/header"
 )
 
+// mode defines when a Set rule changes the header.
+type mode int
+
+const (
+	always    mode = iota // always set the header
+	ifAbsent              // only set the header if it is missing or empty
+	ifPresent             // only set the header if it already exists
+)
+
 type Set struct {
-	rule *types.Rule
+	rule  *types.Rule
+	value *template.Template
+	mode  mode
 }
 
 func New(rule types.Rule) (types.Handler, error) {
-	return &Set{rule: &rule}, nil
+	return newSet(rule, always)
+}
+
+// NewIfAbsent returns a Set handler only setting the header if it is missing or empty.
+func NewIfAbsent(rule types.Rule) (types.Handler, error) {
+	return newSet(rule, ifAbsent)
+}
+
+// NewIfPresent returns a Set handler only setting the header if it already exists.
+func NewIfPresent(rule types.Rule) (types.Handler, error) {
+	return newSet(rule, ifPresent)
+}
+
+func newSet(rule types.Rule, setMode mode) (*Set, error) {
+	value, err := template.Parse(rule.Value)
+	if err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+
+	return &Set{rule: &rule, value: value, mode: setMode}, nil
 }
 
 func (s *Set) Validate() error {
@@ -24,11 +56,42 @@ func (s *Set) Validate() error {
 }
 
 func (s *Set) Handle(rw http.ResponseWriter, req *http.Request) {
+	if !s.shouldSet(rw, req) {
+		return
+	}
+
+	value := header.Resolve(req, s.value.Execute(req), s.rule.HeaderPrefix)
+
 	if s.rule.SetOnResponse {
-		rw.Header().Set(s.rule.Header, s.rule.Value)
+		rw.Header().Set(s.rule.Header, value)
 
 		return
 	}
 
-	header.Set(req, s.rule.Header, s.rule.Value)
+	header.Set(req, s.rule.Header, value)
+}
+
+func (s *Set) shouldSet(rw http.ResponseWriter, req *http.Request) bool {
+	if s.mode == always {
+		return true
+	}
+
+	var values []string
+	if s.rule.SetOnResponse {
+		values = rw.Header().Values(s.rule.Header)
+	} else {
+		values = header.Values(req, s.rule.Header)
+	}
+
+	if s.mode == ifPresent {
+		return len(values) > 0
+	}
+
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+
+	return true
 }
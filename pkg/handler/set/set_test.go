@@ -79,6 +79,50 @@ func TestSetHandler(t *testing.T) {
 			},
 			expectedHost: "example.org",
 		},
+		{
+			name: "Set value from another header",
+			rule: types.Rule{
+				Header:       "X-Test",
+				Value:        "^X-Source",
+				HeaderPrefix: "^",
+			},
+			requestHeaders: map[string]string{
+				"X-Source": "Tested",
+			},
+			wantOnRequest: map[string]string{
+				"X-Source": "Tested",
+				"X-Test":   "Tested",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Set value from Host",
+			rule: types.Rule{
+				Header:        "X-Test",
+				Value:         "^Host",
+				HeaderPrefix:  "^",
+				SetOnResponse: true,
+			},
+			wantOnResponse: map[string]string{
+				"X-Test": "example.com",
+			},
+			expectedHost: "example.com",
+		},
+		{
+			name: "Set template value",
+			rule: types.Rule{
+				Header: "X-Test",
+				Value:  `{{ .Header "Foo" | lower }}-{{ .Method }}-{{ .Path }}`,
+			},
+			requestHeaders: map[string]string{
+				"Foo": "Bar",
+			},
+			wantOnRequest: map[string]string{
+				"Foo":    "Bar",
+				"X-Test": "bar-GET-/foo",
+			},
+			expectedHost: "example.com",
+		},
 	}
 
 	for _, test := range tests {
@@ -112,6 +156,210 @@ func TestSetHandler(t *testing.T) {
 	}
 }
 
+func TestConditionalSetHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		newHandler      func(types.Rule) (types.Handler, error)
+		rule            types.Rule
+		requestHeaders  map[string]string
+		responseHeaders map[string]string
+		wantOnRequest   map[string]string
+		wantOnResponse  map[string]string
+		host            string
+		expectedHost    string
+	}{
+		{
+			name:       "SetIfAbsent missing header",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header: "X-Forwarded-Proto",
+				Value:  "https",
+			},
+			wantOnRequest: map[string]string{
+				"X-Forwarded-Proto": "https",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfAbsent empty header",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header: "X-Forwarded-Proto",
+				Value:  "https",
+			},
+			requestHeaders: map[string]string{
+				"X-Forwarded-Proto": "",
+			},
+			wantOnRequest: map[string]string{
+				"X-Forwarded-Proto": "https",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfAbsent existing header",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header: "X-Forwarded-Proto",
+				Value:  "https",
+			},
+			requestHeaders: map[string]string{
+				"X-Forwarded-Proto": "http",
+			},
+			wantOnRequest: map[string]string{
+				"X-Forwarded-Proto": "http",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfAbsent on response",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header:        "Cache-Control",
+				Value:         "no-store",
+				SetOnResponse: true,
+			},
+			requestHeaders: map[string]string{
+				"Cache-Control": "no-cache",
+			},
+			wantOnRequest: map[string]string{
+				"Cache-Control": "no-cache",
+			},
+			wantOnResponse: map[string]string{
+				"Cache-Control": "no-store",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfAbsent existing Host",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header: "Host",
+				Value:  "example.org",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfAbsent missing Host",
+			newHandler: set.NewIfAbsent,
+			rule: types.Rule{
+				Header: "Host",
+				Value:  "example.org",
+			},
+			host:         "",
+			expectedHost: "example.org",
+		},
+		{
+			name:       "SetIfPresent missing header",
+			newHandler: set.NewIfPresent,
+			rule: types.Rule{
+				Header: "Accept-Language",
+				Value:  "en",
+			},
+			wantOnRequest: map[string]string{
+				"Accept-Language": "",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfPresent existing header",
+			newHandler: set.NewIfPresent,
+			rule: types.Rule{
+				Header: "Accept-Language",
+				Value:  "en",
+			},
+			requestHeaders: map[string]string{
+				"Accept-Language": "fr",
+			},
+			wantOnRequest: map[string]string{
+				"Accept-Language": "en",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfPresent on response",
+			newHandler: set.NewIfPresent,
+			rule: types.Rule{
+				Header:        "Server",
+				Value:         "hidden",
+				SetOnResponse: true,
+			},
+			responseHeaders: map[string]string{
+				"Server": "nginx",
+			},
+			wantOnResponse: map[string]string{
+				"Server": "hidden",
+			},
+			host:         "example.com",
+			expectedHost: "example.com",
+		},
+		{
+			name:       "SetIfPresent missing Host",
+			newHandler: set.NewIfPresent,
+			rule: types.Rule{
+				Header: "Host",
+				Value:  "example.org",
+			},
+			host:         "",
+			expectedHost: "",
+		},
+		{
+			name:       "SetIfPresent existing Host",
+			newHandler: set.NewIfPresent,
+			rule: types.Rule{
+				Header: "Host",
+				Value:  "example.org",
+			},
+			host:         "example.com",
+			expectedHost: "example.org",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Host = test.host
+
+			for hName, hVal := range test.requestHeaders {
+				req.Header.Add(hName, hVal)
+			}
+
+			rw := httptest.NewRecorder()
+			for hName, hVal := range test.responseHeaders {
+				rw.Header().Add(hName, hVal)
+			}
+
+			setHandler, err := test.newHandler(test.rule)
+			require.NoError(t, err)
+
+			setHandler.Handle(rw, req)
+
+			for hName, hVal := range test.wantOnRequest {
+				assert.Equal(t, hVal, req.Header.Get(hName))
+			}
+
+			for hName, hVal := range test.wantOnResponse {
+				assert.Equal(t, hVal, rw.Header().Get(hName))
+			}
+
+			assert.Equal(t, test.expectedHost, req.Host)
+			assert.Equal(t, "example.com", req.URL.Host)
+		})
+	}
+}
+
 func TestValidation(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/encoding"
+	"github.com/tomMoulard/htransformation/pkg/utils/signature"
+)
+
+// HMAC sets a header to the signature of the request method, path and signed headers.
+type HMAC struct {
+	rule   *types.Rule
+	secret []byte
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Algorithm == "" {
+		rule.Algorithm = types.SHA256
+	}
+
+	if rule.Encoding == "" {
+		rule.Encoding = types.EncodingHex
+	}
+
+	secret, err := signature.Secret(&rule)
+	if err != nil {
+		return nil, fmt.Errorf("secret: %w", err)
+	}
+
+	return &HMAC{rule: &rule, secret: secret}, nil
+}
+
+func (h *HMAC) Validate() error {
+	if h.rule.Header == "" || len(h.secret) == 0 {
+		return types.ErrMissingRequiredFields
+	}
+
+	if signature.NewHash(h.rule.Algorithm) == nil {
+		return fmt.Errorf("%w: Algorithm %q", types.ErrInvalidOption, h.rule.Algorithm)
+	}
+
+	if !encoding.Binary(h.rule.Encoding) {
+		return fmt.Errorf("%w: Encoding %q", types.ErrInvalidOption, h.rule.Encoding)
+	}
+
+	if h.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (h *HMAC) Handle(_ http.ResponseWriter, req *http.Request) {
+	sum := signature.Sign(h.rule.Algorithm, h.secret, req, h.rule.SignedHeaders)
+
+	req.Header.Set(h.rule.Header, encoding.Encode(h.rule.Encoding, sum))
+}
@@ -0,0 +1,175 @@
+package signer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/signer"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestHMACHandler(t *testing.T) {
+	t.Parallel()
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600))
+
+	tests := []struct {
+		name   string
+		rule   types.Rule
+		method string
+		want   string
+	}{
+		{
+			name: "signed headers",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				Secret:        "s3cr3t",
+				SignedHeaders: []string{"X-User", "Host"},
+			},
+			method: http.MethodGet,
+			want:   "cdca8b9cc51d3f081b1869615dcf7038cfe4f676755c158bf00071a7011d09f6",
+		},
+		{
+			name: "secret file",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				SecretFile:    secretFile,
+				SignedHeaders: []string{"X-User", "Host"},
+			},
+			method: http.MethodGet,
+			want:   "cdca8b9cc51d3f081b1869615dcf7038cfe4f676755c158bf00071a7011d09f6",
+		},
+		{
+			name: "method and path only",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				Algorithm: types.SHA512,
+				Encoding:  types.EncodingBase64,
+			},
+			method: http.MethodPost,
+			want:   "ZLExHAD6BCBxshhLWpJdxtNPXzNT5ZO8dhBW37XtNCNesGD6aXPQ2SQoBMMAx4t/TDjTW691mExqOZg5XMCjRg==",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), test.method, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Header.Set("X-User", "alice")
+			req.Header.Set("X-Signature", "forged")
+
+			hmacHandler, err := signer.New(test.rule)
+			require.NoError(t, err)
+
+			hmacHandler.Handle(httptest.NewRecorder(), req)
+
+			assert.Equal(t, []string{test.want}, req.Header.Values("X-Signature"))
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "missing secret",
+			rule: types.Rule{
+				Header: "X-Signature",
+				Type:   types.HMAC,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "missing secret file",
+			rule: types.Rule{
+				Header:     "X-Signature",
+				SecretFile: filepath.Join(t.TempDir(), "missing"),
+				Type:       types.HMAC,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "invalid algorithm",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				Algorithm: "MD5",
+				Type:      types.HMAC,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid encoding",
+			rule: types.Rule{
+				Header:   "X-Signature",
+				Secret:   "s3cr3t",
+				Encoding: "Base32",
+				Type:     types.HMAC,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				Secret:        "s3cr3t",
+				Type:          types.HMAC,
+				SetOnResponse: true,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				Secret:        "s3cr3t",
+				SignedHeaders: []string{"X-User"},
+				Type:          types.HMAC,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			hmacHandler, err := signer.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = hmacHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
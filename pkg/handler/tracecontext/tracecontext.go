@@ -0,0 +1,81 @@
+package tracecontext
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+// TraceContext validates the W3C trace context of the request, generating a
+// new one if it is missing or malformed.
+type TraceContext struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	return &TraceContext{rule: &rule}, nil
+}
+
+func (t *TraceContext) Validate() error {
+	for _, format := range t.rule.Mirror {
+		if !trace.Supported(format) {
+			return fmt.Errorf("%w: Mirror %q", types.ErrInvalidOption, format)
+		}
+	}
+
+	if t.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (t *TraceContext) Handle(_ http.ResponseWriter, req *http.Request) {
+	ctx, ok := t.context(req)
+	if !ok {
+		return
+	}
+
+	req.Header.Set("Traceparent", ctx.Traceparent())
+
+	for _, format := range t.rule.Mirror {
+		for name, values := range trace.Encode(format, ctx) {
+			req.Header[name] = values
+		}
+	}
+}
+
+// context returns the trace context of the request, a new one if the
+// traceparent header is invalid, with a new span ID if NewSpan is set.
+func (t *TraceContext) context(req *http.Request) (trace.Context, bool) {
+	var (
+		ctx   trace.Context
+		valid bool
+	)
+
+	// a request with several traceparent headers is invalid.
+	if traceparents := req.Header.Values("Traceparent"); len(traceparents) == 1 {
+		ctx, valid = trace.ParseTraceparent(traceparents[0])
+	}
+
+	if !valid {
+		// the trace state is only relevant to the trace it was received with.
+		req.Header.Del("Tracestate")
+
+		return trace.New()
+	}
+
+	if tracestate := req.Header.Values("Tracestate"); tracestate != nil &&
+		!trace.ValidTracestate(strings.Join(tracestate, ",")) {
+		req.Header.Del("Tracestate")
+	}
+
+	if t.rule.NewSpan {
+		return ctx.Child()
+	}
+
+	return ctx, true
+}
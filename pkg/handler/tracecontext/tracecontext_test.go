@@ -0,0 +1,201 @@
+package tracecontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/tracecontext"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID      = "00f067aa0ba902b7"
+	traceparent = "00-" + traceID + "-" + spanID + "-01"
+)
+
+func TestTraceContextHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		rule           types.Rule
+		requestHeaders http.Header
+		wantTraceID    string
+		wantSpanID     string
+		wantNewSpan    bool
+		wantTracestate string
+		wantHeaders    map[string]string
+	}{
+		{
+			name: "valid trace context",
+			requestHeaders: http.Header{
+				"Traceparent": {traceparent},
+				"Tracestate":  {"congo=t61rcWkgMzE"},
+			},
+			wantTraceID:    traceID,
+			wantSpanID:     spanID,
+			wantTracestate: "congo=t61rcWkgMzE",
+		},
+		{
+			name: "invalid tracestate",
+			requestHeaders: http.Header{
+				"Traceparent": {traceparent},
+				"Tracestate":  {"Congo=t61rcWkgMzE"},
+			},
+			wantTraceID: traceID,
+			wantSpanID:  spanID,
+		},
+		{
+			name: "new span",
+			rule: types.Rule{
+				NewSpan: true,
+			},
+			requestHeaders: http.Header{
+				"Traceparent": {traceparent},
+				"Tracestate":  {"congo=t61rcWkgMzE"},
+			},
+			wantTraceID:    traceID,
+			wantNewSpan:    true,
+			wantTracestate: "congo=t61rcWkgMzE",
+		},
+		{
+			name: "missing trace context",
+		},
+		{
+			name: "malformed traceparent",
+			requestHeaders: http.Header{
+				"Traceparent": {"00-" + traceID + "-" + spanID},
+				"Tracestate":  {"congo=t61rcWkgMzE"},
+			},
+		},
+		{
+			name: "several traceparent headers",
+			requestHeaders: http.Header{
+				"Traceparent": {traceparent, traceparent},
+			},
+		},
+		{
+			name: "mirror",
+			rule: types.Rule{
+				Mirror: []types.TraceFormat{types.TraceB3, types.TraceCloudTrace},
+			},
+			requestHeaders: http.Header{
+				"Traceparent": {traceparent},
+			},
+			wantTraceID: traceID,
+			wantSpanID:  spanID,
+			wantHeaders: map[string]string{
+				"X-B3-Traceid":          traceID,
+				"X-B3-Spanid":           spanID,
+				"X-B3-Sampled":          "1",
+				"X-Cloud-Trace-Context": traceID + "/67667974448284343;o=1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			for name, values := range test.requestHeaders {
+				req.Header[name] = values
+			}
+
+			traceContextHandler, err := tracecontext.New(test.rule)
+			require.NoError(t, err)
+
+			traceContextHandler.Handle(httptest.NewRecorder(), req)
+
+			assert.Equal(t, 1, len(req.Header.Values("Traceparent")))
+
+			ctx, valid := trace.ParseTraceparent(req.Header.Get("Traceparent"))
+			assert.Equal(t, true, valid)
+
+			if test.wantTraceID != "" {
+				assert.Equal(t, test.wantTraceID, ctx.TraceID)
+			} else {
+				assert.Equal(t, false, ctx.TraceID == traceID)
+			}
+
+			if test.wantSpanID != "" {
+				assert.Equal(t, test.wantSpanID, ctx.SpanID)
+			}
+
+			if test.wantNewSpan {
+				assert.Equal(t, false, ctx.SpanID == spanID)
+			}
+
+			assert.Equal(t, test.wantTracestate, req.Header.Get("Tracestate"))
+
+			for name, value := range test.wantHeaders {
+				assert.Equal(t, value, req.Header.Get(name))
+			}
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: false,
+		},
+		{
+			name: "invalid mirror format",
+			rule: types.Rule{
+				Mirror: []types.TraceFormat{types.TraceB3, "OpenTracing"},
+				Type:   types.TraceContext,
+			},
+			wantErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Type:          types.TraceContext,
+				SetOnResponse: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				NewSpan: true,
+				Mirror:  []types.TraceFormat{types.TraceB3, types.TraceXRay},
+				Type:    types.TraceContext,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			traceContextHandler, err := tracecontext.New(test.rule)
+			require.NoError(t, err)
+
+			err = traceContextHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package traceconvert
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+// TraceConvert translates the trace context propagated in a format to another format.
+type TraceConvert struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	return &TraceConvert{rule: &rule}, nil
+}
+
+func (t *TraceConvert) Validate() error {
+	if t.rule.From == "" || t.rule.To == "" {
+		return types.ErrMissingRequiredFields
+	}
+
+	for _, format := range []types.TraceFormat{t.rule.From, t.rule.To} {
+		if !trace.Supported(format) {
+			return fmt.Errorf("%w: trace format %q", types.ErrInvalidOption, format)
+		}
+	}
+
+	return nil
+}
+
+func (t *TraceConvert) Handle(rw http.ResponseWriter, req *http.Request) {
+	headers := req.Header
+	if t.rule.SetOnResponse {
+		headers = rw.Header()
+	}
+
+	ctx, valid := trace.Decode(t.rule.From, headers)
+	if !valid {
+		return
+	}
+
+	if t.rule.RemoveSource {
+		for _, name := range trace.Headers(t.rule.From) {
+			headers.Del(name)
+		}
+	}
+
+	// stale headers of the target format, like a B3 parent span ID, would no longer match the trace context.
+	for _, name := range trace.Headers(t.rule.To) {
+		headers.Del(name)
+	}
+
+	for name, values := range trace.Encode(t.rule.To, ctx) {
+		headers[name] = values
+	}
+}
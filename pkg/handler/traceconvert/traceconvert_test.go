@@ -0,0 +1,210 @@
+package traceconvert_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/traceconvert"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestTraceConvertHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		headers     http.Header
+		wantHeaders http.Header
+	}{
+		{
+			name: "B3 single to B3",
+			rule: types.Rule{
+				From: types.TraceB3Single,
+				To:   types.TraceB3,
+			},
+			headers: http.Header{
+				"B3":                {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+				"X-B3-Parentspanid": {"05e3ac9a4f6e3b90"},
+			},
+			wantHeaders: http.Header{
+				"B3":           {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+				"X-B3-Spanid":  {"00f067aa0ba902b7"},
+				"X-B3-Sampled": {"1"},
+			},
+		},
+		{
+			name: "Jaeger to W3C removing the source",
+			rule: types.Rule{
+				From:         types.TraceJaeger,
+				To:           types.TraceW3C,
+				RemoveSource: true,
+			},
+			headers: http.Header{
+				"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+			},
+			wantHeaders: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+		},
+		{
+			name: "X-Ray to W3C",
+			rule: types.Rule{
+				From:         types.TraceXRay,
+				To:           types.TraceW3C,
+				RemoveSource: true,
+			},
+			headers: http.Header{
+				"X-Amzn-Trace-Id": {"Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7;Sampled=0"},
+				"Traceparent":     {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+			wantHeaders: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+			},
+		},
+		{
+			name: "W3C to X-Ray",
+			rule: types.Rule{
+				From: types.TraceW3C,
+				To:   types.TraceXRay,
+			},
+			headers: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+			wantHeaders: http.Header{
+				"Traceparent":     {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"X-Amzn-Trace-Id": {"Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7;Sampled=1"},
+			},
+		},
+		{
+			name: "invalid source",
+			rule: types.Rule{
+				From:         types.TraceB3,
+				To:           types.TraceW3C,
+				RemoveSource: true,
+			},
+			headers: http.Header{
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+				"Traceparent":  {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+			wantHeaders: http.Header{
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+				"Traceparent":  {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			},
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				From:          types.TraceW3C,
+				To:            types.TraceCloudTrace,
+				SetOnResponse: true,
+			},
+			headers: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+			wantHeaders: http.Header{
+				"Traceparent":           {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"X-Cloud-Trace-Context": {"4bf92f3577b34da6a3ce929d0e0e4736/67667974448284343;o=1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			headers := req.Header
+			if test.rule.SetOnResponse {
+				headers = rw.Header()
+			}
+
+			for name, values := range test.headers {
+				headers[name] = values
+			}
+
+			traceConvertHandler, err := traceconvert.New(test.rule)
+			require.NoError(t, err)
+
+			traceConvertHandler.Handle(rw, req)
+
+			assert.Equal(t, test.wantHeaders, headers)
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: true,
+		},
+		{
+			name: "missing target",
+			rule: types.Rule{
+				From: types.TraceB3,
+				Type: types.TraceConvert,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid source",
+			rule: types.Rule{
+				From: "OpenTracing",
+				To:   types.TraceW3C,
+				Type: types.TraceConvert,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid target",
+			rule: types.Rule{
+				From: types.TraceW3C,
+				To:   "OpenTracing",
+				Type: types.TraceConvert,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				From: types.TraceB3Single,
+				To:   types.TraceB3,
+				Type: types.TraceConvert,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			traceConvertHandler, err := traceconvert.New(test.rule)
+			require.NoError(t, err)
+
+			err = traceConvertHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
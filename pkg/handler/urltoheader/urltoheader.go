@@ -0,0 +1,132 @@
+package urltoheader
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/query"
+)
+
+// defaultPathValue is the value of the header when it is read from the path and no Value is given.
+const defaultPathValue = "$1"
+
+// URLToHeader sets a header from a query parameter, the path or the fragment of the request URL.
+type URLToHeader struct {
+	rule *types.Rule
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Path != "" {
+		re, err := regexp.Compile(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidRegexp, rule.Name)
+		}
+
+		rule.Regexp = re
+
+		if rule.Value == "" {
+			rule.Value = defaultPathValue
+		}
+	}
+
+	return &URLToHeader{rule: &rule}, nil
+}
+
+func (u *URLToHeader) Validate() error {
+	sources := 0
+
+	for _, set := range []bool{u.rule.Query != "", u.rule.Path != "", u.rule.Fragment} {
+		if set {
+			sources++
+		}
+	}
+
+	if u.rule.Header == "" || sources == 0 {
+		return types.ErrMissingRequiredFields
+	}
+
+	if sources > 1 {
+		return fmt.Errorf("%w: only one of Query, Path and Fragment can be set", types.ErrInvalidOption)
+	}
+
+	if u.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return nil
+}
+
+func (u *URLToHeader) Handle(_ http.ResponseWriter, req *http.Request) {
+	var (
+		value string
+		found bool
+	)
+
+	switch {
+	case u.rule.Query != "":
+		value, found = u.fromQuery(req.URL)
+	case u.rule.Path != "":
+		value, found = u.fromPath(req.URL)
+	default:
+		value, found = req.URL.Fragment, req.URL.Fragment != ""
+		if found && u.rule.RemoveSource {
+			req.URL.Fragment = ""
+			req.URL.RawFragment = ""
+		}
+	}
+
+	if found {
+		header.Set(req, u.rule.Header, value)
+	}
+}
+
+// fromQuery returns the first value of the query parameter, removing every
+// value of the parameter from the URL if RemoveSource is set.
+func (u *URLToHeader) fromQuery(reqURL *url.URL) (string, bool) {
+	values, ok := reqURL.Query()[u.rule.Query]
+	if !ok {
+		return "", false
+	}
+
+	if u.rule.RemoveSource {
+		reqURL.RawQuery = query.Remove(reqURL.RawQuery, u.rule.Query)
+	}
+
+	return values[0], true
+}
+
+// fromPath returns the Value expanded with the capture groups of the escaped path, then unescaped,
+// removing the matched part of the escaped path if RemoveSource is set.
+func (u *URLToHeader) fromPath(reqURL *url.URL) (string, bool) {
+	escaped := reqURL.EscapedPath()
+
+	match := u.rule.Regexp.FindStringSubmatchIndex(escaped)
+	if match == nil {
+		return "", false
+	}
+
+	value := string(u.rule.Regexp.ExpandString(nil, u.rule.Value, escaped, match))
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	if u.rule.RemoveSource {
+		rawPath := escaped[:match[0]] + escaped[match[1]:]
+		if !strings.HasPrefix(rawPath, "/") {
+			rawPath = "/" + rawPath
+		}
+
+		// the escaped path is valid, so is the remaining part.
+		path, _ := url.PathUnescape(rawPath)
+
+		reqURL.Path = path
+		reqURL.RawPath = rawPath
+	}
+
+	return value, true
+}
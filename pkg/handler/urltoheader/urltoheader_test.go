@@ -0,0 +1,289 @@
+package urltoheader_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/urltoheader"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+func TestURLToHeaderHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rule          types.Rule
+		url           string
+		wantHeader    []string
+		wantURL       string
+		wantPath      string
+		requestHeader string
+	}{
+		{
+			name: "query parameter",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+			},
+			url:        "http://example.com/foo?tenant=acme&page=2",
+			wantHeader: []string{"acme"},
+			wantURL:    "http://example.com/foo?tenant=acme&page=2",
+		},
+		{
+			name: "remove query parameter",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Query:        "tenant",
+				RemoveSource: true,
+			},
+			url:        "http://example.com/foo?z=1&tenant=acme&a=2&tenant=other",
+			wantHeader: []string{"acme"},
+			wantURL:    "http://example.com/foo?z=1&a=2",
+		},
+		{
+			name: "encoded query parameter",
+			rule: types.Rule{
+				Header:       "X-Tenant",
+				Query:        "tenant id",
+				RemoveSource: true,
+			},
+			url:        "http://example.com/foo?tenant+id=acme%20corp",
+			wantHeader: []string{"acme corp"},
+			wantURL:    "http://example.com/foo",
+		},
+		{
+			name: "missing query parameter",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+			},
+			url:           "http://example.com/foo?page=2",
+			requestHeader: "existing",
+			wantHeader:    []string{"existing"},
+			wantURL:       "http://example.com/foo?page=2",
+		},
+		{
+			name: "query parameter over existing header",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+			},
+			url:           "http://example.com/foo?tenant=acme",
+			requestHeader: "existing",
+			wantHeader:    []string{"acme"},
+			wantURL:       "http://example.com/foo?tenant=acme",
+		},
+		{
+			name: "path capture group",
+			rule: types.Rule{
+				Header: "X-Api-Version",
+				Path:   `^/(v\d+)/`,
+			},
+			url:        "http://example.com/v2/users",
+			wantHeader: []string{"v2"},
+			wantURL:    "http://example.com/v2/users",
+		},
+		{
+			name: "path named capture group",
+			rule: types.Rule{
+				Header: "X-Api-Version",
+				Path:   `^/v(?P<version>\d+)`,
+				Value:  "version-${version}",
+			},
+			url:        "http://example.com/v2/users",
+			wantHeader: []string{"version-2"},
+			wantURL:    "http://example.com/v2/users",
+		},
+		{
+			name: "remove path part",
+			rule: types.Rule{
+				Header:       "X-Api-Version",
+				Path:         `^/(v\d+)/`,
+				RemoveSource: true,
+			},
+			url:        "http://example.com/v2/users?page=2",
+			wantHeader: []string{"v2"},
+			wantURL:    "http://example.com/users?page=2",
+		},
+		{
+			name: "remove path part keeping escaped slashes",
+			rule: types.Rule{
+				Header:       "X-Api-Version",
+				Path:         `^/(v\d+)`,
+				RemoveSource: true,
+			},
+			url:        "http://example.com/v2/files/a%2Fb",
+			wantHeader: []string{"v2"},
+			wantURL:    "http://example.com/files/a%2Fb",
+			wantPath:   "/files/a/b",
+		},
+		{
+			name: "escaped capture group",
+			rule: types.Rule{
+				Header: "X-File",
+				Path:   `^/files/([^/]+)$`,
+			},
+			url:        "http://example.com/files/a%2Fb%20c",
+			wantHeader: []string{"a/b c"},
+			wantURL:    "http://example.com/files/a%2Fb%20c",
+			wantPath:   "/files/a/b c",
+		},
+		{
+			name: "path not matching",
+			rule: types.Rule{
+				Header: "X-Api-Version",
+				Path:   `^/(v\d+)/`,
+			},
+			url:        "http://example.com/users",
+			wantHeader: nil,
+			wantURL:    "http://example.com/users",
+		},
+		{
+			name: "fragment",
+			rule: types.Rule{
+				Header:       "X-Section",
+				Fragment:     true,
+				RemoveSource: true,
+			},
+			url:        "http://example.com/foo#intro",
+			wantHeader: []string{"intro"},
+			wantURL:    "http://example.com/foo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+
+			if test.requestHeader != "" {
+				req.Header.Set(test.rule.Header, test.requestHeader)
+			}
+
+			urlToHeaderHandler, err := urltoheader.New(test.rule)
+			require.NoError(t, err)
+
+			urlToHeaderHandler.Handle(nil, req)
+
+			assert.Equal(t, test.wantHeader, req.Header.Values(test.rule.Header))
+			assert.Equal(t, test.wantURL, req.URL.String())
+
+			if test.wantPath != "" {
+				assert.Equal(t, test.wantPath, req.URL.Path)
+			}
+		})
+	}
+}
+
+func TestURLToHeaderHost(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo?host=example.org", nil)
+	require.NoError(t, err)
+
+	urlToHeaderHandler, err := urltoheader.New(types.Rule{Header: "Host", Query: "host"})
+	require.NoError(t, err)
+
+	urlToHeaderHandler.Handle(nil, req)
+
+	assert.Equal(t, "example.org", req.Host)
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		rule            types.Rule
+		wantNewErr      bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "no rules",
+			wantValidateErr: true,
+		},
+		{
+			name: "missing source",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Type:   types.URLToHeader,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "missing header",
+			rule: types.Rule{
+				Query: "tenant",
+				Type:  types.URLToHeader,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "several sources",
+			rule: types.Rule{
+				Header:   "X-Tenant",
+				Query:    "tenant",
+				Fragment: true,
+				Type:     types.URLToHeader,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "invalid regexp",
+			rule: types.Rule{
+				Header: "X-Api-Version",
+				Path:   "(",
+				Type:   types.URLToHeader,
+			},
+			wantNewErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Tenant",
+				Query:         "tenant",
+				Type:          types.URLToHeader,
+				SetOnResponse: true,
+			},
+			wantValidateErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header: "X-Tenant",
+				Query:  "tenant",
+				Type:   types.URLToHeader,
+			},
+			wantNewErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlToHeaderHandler, err := urltoheader.New(test.rule)
+			if test.wantNewErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			err = urlToHeaderHandler.Validate()
+			t.Log(err)
+
+			if test.wantValidateErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
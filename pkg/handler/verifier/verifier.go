@@ -0,0 +1,119 @@
+package verifier
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/encoding"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+	"github.com/tomMoulard/htransformation/pkg/utils/signature"
+)
+
+// VerifyHMAC checks the signature of the request method, path and signed headers, and applies the failure policy to
+// the requests with a missing or invalid signature.
+type VerifyHMAC struct {
+	rule   *types.Rule
+	secret []byte
+}
+
+func New(rule types.Rule) (types.Handler, error) {
+	if rule.Algorithm == "" {
+		rule.Algorithm = types.SHA256
+	}
+
+	if rule.Encoding == "" {
+		rule.Encoding = types.EncodingHex
+	}
+
+	if rule.OnFailure == "" {
+		rule.OnFailure = types.FailureReject
+	}
+
+	if rule.StatusCode == 0 {
+		rule.StatusCode = http.StatusUnauthorized
+	}
+
+	secret, err := signature.Secret(&rule)
+	if err != nil {
+		return nil, fmt.Errorf("secret: %w", err)
+	}
+
+	return &VerifyHMAC{rule: &rule, secret: secret}, nil
+}
+
+func (v *VerifyHMAC) Validate() error {
+	if v.rule.Header == "" || len(v.secret) == 0 {
+		return types.ErrMissingRequiredFields
+	}
+
+	if signature.NewHash(v.rule.Algorithm) == nil {
+		return fmt.Errorf("%w: Algorithm %q", types.ErrInvalidOption, v.rule.Algorithm)
+	}
+
+	if !encoding.Binary(v.rule.Encoding) {
+		return fmt.Errorf("%w: Encoding %q", types.ErrInvalidOption, v.rule.Encoding)
+	}
+
+	if v.rule.SetOnResponse {
+		return types.ErrRequestOnly
+	}
+
+	return v.validateFailure()
+}
+
+func (v *VerifyHMAC) validateFailure() error {
+	switch v.rule.OnFailure {
+	case types.FailureDelete, types.FailureReject:
+	case types.FailureFlag:
+		if v.rule.FlagHeader == "" {
+			return types.ErrMissingRequiredFields
+		}
+	case types.FailureLeave:
+		return fmt.Errorf("%w: OnFailure %q would forward tampered headers", types.ErrInvalidOption, v.rule.OnFailure)
+	default:
+		return fmt.Errorf("%w: OnFailure %q", types.ErrInvalidOption, v.rule.OnFailure)
+	}
+
+	if v.rule.StatusCode < http.StatusOK || v.rule.StatusCode > 599 {
+		return fmt.Errorf("%w: StatusCode %d", types.ErrInvalidOption, v.rule.StatusCode)
+	}
+
+	return nil
+}
+
+func (v *VerifyHMAC) Handle(rw http.ResponseWriter, req *http.Request) {
+	valid := v.verify(req)
+
+	switch v.rule.OnFailure {
+	case types.FailureFlag:
+		// the flag is always set, so that it cannot be forged by the client.
+		req.Header.Set(v.rule.FlagHeader, strconv.FormatBool(valid))
+	case types.FailureDelete:
+		// the Host is needed to route the request, it is never deleted.
+		if !valid {
+			for _, name := range append([]string{v.rule.Header}, v.rule.SignedHeaders...) {
+				req.Header.Del(name)
+			}
+		}
+	case types.FailureReject:
+		if !valid {
+			response.Write(rw, v.rule.StatusCode, v.rule.Body)
+		}
+	case types.FailureLeave:
+	}
+}
+
+// verify reports whether the request holds exactly one signature, matching the expected one.
+func (v *VerifyHMAC) verify(req *http.Request) bool {
+	signatures := req.Header.Values(v.rule.Header)
+	if len(signatures) != 1 {
+		return false
+	}
+
+	expected := encoding.Encode(v.rule.Encoding, signature.Sign(v.rule.Algorithm, v.secret, req, v.rule.SignedHeaders))
+
+	return hmac.Equal([]byte(signatures[0]), []byte(expected))
+}
@@ -0,0 +1,252 @@
+package verifier_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/handler/verifier"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// validSignature is the HMAC-SHA256 of "GET\n/foo\nx-user:alice" with the key "s3cr3t".
+const validSignature = "36d205ea4a9916cba0445ae4de9161b377f568cdc0a2e533534962d61c8bdc73"
+
+func TestVerifyHMACHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rule        types.Rule
+		signatures  []string
+		user        string
+		wantHeaders http.Header
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:       "valid signature",
+			signatures: []string{validSignature},
+			user:       "alice",
+			wantHeaders: http.Header{
+				"X-Signature": {validSignature},
+				"X-User":      {"alice"},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "tampered header rejected",
+			signatures:  []string{validSignature},
+			user:        "mallory",
+			wantHeaders: http.Header{"X-Signature": {validSignature}, "X-User": {"mallory"}},
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name: "missing signature rejected with status and body",
+			rule: types.Rule{
+				StatusCode: http.StatusForbidden,
+				Body:       "invalid signature",
+			},
+			user:        "alice",
+			wantHeaders: http.Header{"X-User": {"alice"}},
+			wantStatus:  http.StatusForbidden,
+			wantBody:    "invalid signature",
+		},
+		{
+			name:        "several signatures rejected",
+			signatures:  []string{validSignature, validSignature},
+			user:        "alice",
+			wantHeaders: http.Header{"X-Signature": {validSignature, validSignature}, "X-User": {"alice"}},
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name: "tampered header deleted",
+			rule: types.Rule{
+				OnFailure: types.FailureDelete,
+			},
+			signatures:  []string{validSignature},
+			user:        "mallory",
+			wantHeaders: http.Header{},
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name: "tampered header flagged",
+			rule: types.Rule{
+				OnFailure:  types.FailureFlag,
+				FlagHeader: "X-Signature-Valid",
+			},
+			signatures: []string{validSignature},
+			user:       "mallory",
+			wantHeaders: http.Header{
+				"X-Signature":       {validSignature},
+				"X-User":            {"mallory"},
+				"X-Signature-Valid": {"false"},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "valid header flagged",
+			rule: types.Rule{
+				OnFailure:  types.FailureFlag,
+				FlagHeader: "X-Signature-Valid",
+			},
+			signatures: []string{validSignature},
+			user:       "alice",
+			wantHeaders: http.Header{
+				"X-Signature":       {validSignature},
+				"X-User":            {"alice"},
+				"X-Signature-Valid": {"true"},
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+			require.NoError(t, err)
+
+			req.Header["X-Signature"] = test.signatures
+			if test.signatures == nil {
+				delete(req.Header, "X-Signature")
+			}
+
+			req.Header.Set("X-User", test.user)
+
+			rule := test.rule
+			rule.Header = "X-Signature"
+			rule.Secret = "s3cr3t"
+			rule.SignedHeaders = []string{"X-User"}
+
+			verifyHandler, err := verifier.New(rule)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+
+			verifyHandler.Handle(rw, req)
+
+			assert.Equal(t, test.wantHeaders, req.Header)
+			assert.Equal(t, test.wantStatus, rw.Code)
+			assert.Equal(t, test.wantBody, rw.Body.String())
+		})
+	}
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			wantErr: true,
+		},
+		{
+			name: "invalid failure policy",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				OnFailure: "Ignore",
+				Type:      types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "leave failure policy",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				OnFailure: types.FailureLeave,
+				Type:      types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "flag without header",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				OnFailure: types.FailureFlag,
+				Type:      types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid status code",
+			rule: types.Rule{
+				Header:     "X-Signature",
+				Secret:     "s3cr3t",
+				StatusCode: 1000,
+				Type:       types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "informational status code",
+			rule: types.Rule{
+				Header:     "X-Signature",
+				Secret:     "s3cr3t",
+				StatusCode: http.StatusContinue,
+				Type:       types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid algorithm",
+			rule: types.Rule{
+				Header:    "X-Signature",
+				Secret:    "s3cr3t",
+				Algorithm: "MD5",
+				Type:      types.VerifyHMAC,
+			},
+			wantErr: true,
+		},
+		{
+			name: "on response",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				Secret:        "s3cr3t",
+				Type:          types.VerifyHMAC,
+				SetOnResponse: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid rule",
+			rule: types.Rule{
+				Header:        "X-Signature",
+				Secret:        "s3cr3t",
+				SignedHeaders: []string{"X-User"},
+				OnFailure:     types.FailureFlag,
+				FlagHeader:    "X-Signature-Valid",
+				Type:          types.VerifyHMAC,
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifyHandler, err := verifier.New(test.rule)
+			require.NoError(t, err)
+
+			err = verifyHandler.Validate()
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package template
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+// arities of the template functions and fields.
+const (
+	noArgs = iota
+	oneArg
+	twoArgs
+	threeArgs
+)
+
+// function is a template function or field, called with exactly arity arguments.
+type function struct {
+	arity int
+	call  func(req *http.Request, args []string) string
+}
+
+// functions returns the helper functions available in templates.
+func functions() map[string]function {
+	return map[string]function{
+		"lower": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return strings.ToLower(args[0])
+		}},
+		"upper": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return strings.ToUpper(args[0])
+		}},
+		"trim": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return strings.TrimSpace(args[0])
+		}},
+		// default returns the value, or the default one if the value is empty.
+		"default": {arity: twoArgs, call: func(_ *http.Request, args []string) string {
+			if args[1] == "" {
+				return args[0]
+			}
+
+			return args[1]
+		}},
+		// replace replaces every occurrence of old by new in the value.
+		"replace": {arity: threeArgs, call: func(_ *http.Request, args []string) string {
+			return strings.ReplaceAll(args[2], args[0], args[1])
+		}},
+		"base64": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return base64.StdEncoding.EncodeToString([]byte(args[0]))
+		}},
+		"urlencode": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return url.QueryEscape(args[0])
+		}},
+		"pathescape": {arity: oneArg, call: func(_ *http.Request, args []string) string {
+			return url.PathEscape(args[0])
+		}},
+	}
+}
+
+// fields returns the request fields available in templates.
+func fields() map[string]function {
+	return map[string]function{
+		"Header": {arity: oneArg, call: func(req *http.Request, args []string) string {
+			if values := header.Values(req, args[0]); len(values) > 0 {
+				return values[0]
+			}
+
+			return ""
+		}},
+		"Query": {arity: oneArg, call: func(req *http.Request, args []string) string {
+			return req.URL.Query().Get(args[0])
+		}},
+		"Path": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			return req.URL.Path
+		}},
+		"EscapedPath": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			return req.URL.EscapedPath()
+		}},
+		"Method": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			return req.Method
+		}},
+		"Host": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			return req.Host
+		}},
+		"RemoteAddr": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			return req.RemoteAddr
+		}},
+		// Status is the response status code, it is empty on the request.
+		"Status": {arity: noArgs, call: func(req *http.Request, _ []string) string {
+			if code := state.FromRequest(req).StatusCode; code != 0 {
+				return strconv.Itoa(code)
+			}
+
+			return ""
+		}},
+	}
+}
+
+// lookup returns the function or field called by the token, or nil if the token is not a call.
+func lookup(tok token) (*function, error) {
+	var (
+		callee function
+		found  bool
+	)
+
+	switch tok.typ {
+	case tokenIdentifier:
+		callee, found = functions()[tok.value]
+	case tokenField:
+		callee, found = fields()[tok.value]
+	case tokenString, tokenPipe, tokenLeftParen, tokenRightParen:
+		return nil, nil //nolint:nilnil // the token is an operand.
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: unknown function %q", types.ErrInvalidTemplate, tok.value)
+	}
+
+	return &callee, nil
+}
@@ -0,0 +1,139 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// tokenType identifies the type of lexical tokens.
+type tokenType int
+
+const (
+	tokenString     tokenType = iota // quoted string, unquoted in the token value
+	tokenIdentifier                  // function name
+	tokenField                       // field name, without the leading dot
+	tokenPipe                        // pipe symbol
+	tokenLeftParen                   // '('
+	tokenRightParen                  // ')'
+)
+
+// token is a lexical token of a template action.
+type token struct {
+	typ   tokenType
+	value string
+}
+
+// lexAction splits the content of an action (between the delimiters) into tokens.
+func lexAction(action string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(action); {
+		if strings.IndexByte(" \t\r\n", action[pos]) != -1 {
+			pos++
+
+			continue
+		}
+
+		tok, end, err := lexToken(action, pos)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, tok)
+		pos = end
+	}
+
+	return tokens, nil
+}
+
+// lexToken reads the token starting at pos, it returns the token and the position right after it.
+func lexToken(action string, pos int) (token, int, error) {
+	switch action[pos] {
+	case '|':
+		return token{typ: tokenPipe, value: "|"}, pos + 1, nil
+	case '(':
+		return token{typ: tokenLeftParen, value: "("}, pos + 1, nil
+	case ')':
+		return token{typ: tokenRightParen, value: ")"}, pos + 1, nil
+	case '"', '`':
+		value, end, err := lexString(action, pos)
+
+		return token{typ: tokenString, value: value}, end, err
+	case '.':
+		name := lexIdentifier(action[pos+1:])
+		if name == "" {
+			return token{typ: tokenIdentifier, value: ""}, 0,
+				fmt.Errorf("%w: missing field name in %q", types.ErrInvalidTemplate, action)
+		}
+
+		return token{typ: tokenField, value: name}, pos + len(name) + 1, nil
+	}
+
+	name := lexIdentifier(action[pos:])
+	if name == "" {
+		return token{typ: tokenIdentifier, value: ""}, 0,
+			fmt.Errorf("%w: unexpected %q in %q", types.ErrInvalidTemplate, action[pos], action)
+	}
+
+	return token{typ: tokenIdentifier, value: name}, pos + len(name), nil
+}
+
+// lexString reads the quoted string starting at start, it returns its unquoted
+// value and the position right after the closing quote.
+func lexString(action string, start int) (string, int, error) {
+	quote := action[start]
+
+	for pos := start + 1; pos < len(action); pos++ {
+		switch action[pos] {
+		case '\\':
+			if quote == '"' {
+				pos++
+			}
+		case quote:
+			value, err := strconv.Unquote(action[start : pos+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("%w: invalid string %s", types.ErrInvalidTemplate, action[start:pos+1])
+			}
+
+			return value, pos + 1, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string in %q", types.ErrInvalidTemplate, action)
+}
+
+// lexIdentifier returns the identifier at the start of text.
+func lexIdentifier(text string) string {
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_'
+	})
+	if end == -1 {
+		return text
+	}
+
+	return text[:end]
+}
+
+// actionEnd returns the position of the delimiter closing the action starting
+// at the beginning of text, ignoring delimiters inside strings, or -1 if there is none.
+func actionEnd(text string) int {
+	var quote byte
+
+	for pos := 0; pos < len(text); pos++ {
+		switch {
+		case quote == 0 && strings.HasPrefix(text[pos:], rightDelim):
+			return pos
+		case quote == 0 && (text[pos] == '"' || text[pos] == '`'):
+			quote = text[pos]
+		case quote == '"' && text[pos] == '\\':
+			pos++
+		case quote != 0 && text[pos] == quote:
+			quote = 0
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,297 @@
+package template
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+const (
+	leftDelim  = "{{"
+	rightDelim = "}}"
+)
+
+// Template is a compiled rule value, made of literal text and actions such as
+// {{ .Header "X-User" | lower }} evaluated against the request.
+type Template struct {
+	nodes []node
+}
+
+// node is a part of a template.
+type node interface {
+	eval(req *http.Request) string
+}
+
+// textNode is a literal string.
+type textNode string
+
+func (t textNode) eval(_ *http.Request) string {
+	return string(t)
+}
+
+// pipelineNode is a chain of commands, the result of each command being passed
+// as the last argument of the next one.
+type pipelineNode []command
+
+func (p pipelineNode) eval(req *http.Request) string {
+	var piped []string
+
+	for _, cmd := range p {
+		piped = []string{cmd.eval(req, piped)}
+	}
+
+	return piped[0]
+}
+
+// command is a call to a function or a field, or a single operand if fn is nil.
+type command struct {
+	fn   *function
+	args []node
+}
+
+func (c command) eval(req *http.Request, piped []string) string {
+	if c.fn == nil {
+		return c.args[0].eval(req)
+	}
+
+	args := make([]string, 0, len(c.args)+len(piped))
+	for _, arg := range c.args {
+		args = append(args, arg.eval(req))
+	}
+
+	return c.fn.call(req, append(args, piped...))
+}
+
+// Parse compiles the given text into a Template.
+func Parse(text string) (*Template, error) {
+	tmpl := &Template{nodes: nil}
+
+	for text != "" {
+		start := strings.Index(text, leftDelim)
+		if start == -1 {
+			tmpl.nodes = append(tmpl.nodes, textNode(text))
+
+			break
+		}
+
+		if start > 0 {
+			tmpl.nodes = append(tmpl.nodes, textNode(text[:start]))
+		}
+
+		text = text[start+len(leftDelim):]
+
+		end := actionEnd(text)
+		if end == -1 {
+			return nil, fmt.Errorf("%w: unclosed action", types.ErrInvalidTemplate)
+		}
+
+		pipeline, err := parseAction(text[:end])
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl.nodes = append(tmpl.nodes, pipeline)
+		text = text[end+len(rightDelim):]
+	}
+
+	return tmpl, nil
+}
+
+// ParseAll compiles each of the given texts into a Template.
+func ParseAll(texts []string) ([]*Template, error) {
+	tmpls := make([]*Template, 0, len(texts))
+
+	for _, text := range texts {
+		tmpl, err := Parse(text)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpls = append(tmpls, tmpl)
+	}
+
+	return tmpls, nil
+}
+
+// Execute evaluates the template against the request.
+func (t *Template) Execute(req *http.Request) string {
+	if len(t.nodes) == 1 {
+		return t.nodes[0].eval(req)
+	}
+
+	var builder strings.Builder
+	for _, n := range t.nodes {
+		builder.WriteString(n.eval(req))
+	}
+
+	return builder.String()
+}
+
+func parseAction(action string) (pipelineNode, error) {
+	tokens, err := lexAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := &parser{tokens: tokens, pos: 0}
+
+	pipeline, err := prs.parsePipeline()
+	if err != nil {
+		return nil, fmt.Errorf("%w in %q", err, action)
+	}
+
+	if tok, ok := prs.next(); ok {
+		return nil, fmt.Errorf("%w: unexpected %q in %q", types.ErrInvalidTemplate, tok.value, action)
+	}
+
+	return pipeline, nil
+}
+
+// parser builds the nodes of an action from its tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) next() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{typ: tokenIdentifier, value: ""}, false
+	}
+
+	p.pos++
+
+	return p.tokens[p.pos-1], true
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{typ: tokenIdentifier, value: ""}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parsePipeline() (pipelineNode, error) {
+	var pipeline pipelineNode
+
+	for {
+		cmd, err := p.parseCommand(len(pipeline) > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = append(pipeline, cmd)
+
+		if tok, ok := p.peek(); !ok || tok.typ != tokenPipe {
+			return pipeline, nil
+		}
+
+		p.pos++
+	}
+}
+
+func (p *parser) parseCommand(piped bool) (command, error) {
+	tok, ok := p.peek()
+	if !ok || tok.typ == tokenPipe || tok.typ == tokenRightParen {
+		return command{}, fmt.Errorf("%w: missing command", types.ErrInvalidTemplate)
+	}
+
+	callee, err := lookup(tok)
+	if err != nil {
+		return command{}, err
+	}
+
+	if callee == nil {
+		return p.parseOperandCommand(piped)
+	}
+
+	p.pos++
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return command{}, err
+	}
+
+	nbArgs := len(args)
+	if piped {
+		nbArgs++
+	}
+
+	if nbArgs != callee.arity {
+		return command{}, fmt.Errorf("%w: %s expects %d arguments, got %d",
+			types.ErrInvalidTemplate, tok.value, callee.arity, nbArgs)
+	}
+
+	return command{fn: callee, args: args}, nil
+}
+
+// parseArgs parses the operands up to the end of the command.
+func (p *parser) parseArgs() ([]node, error) {
+	var args []node
+
+	for {
+		if tok, ok := p.peek(); !ok || tok.typ == tokenPipe || tok.typ == tokenRightParen {
+			return args, nil
+		}
+
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+}
+
+// parseOperandCommand parses a command made of a single operand, such as a string.
+func (p *parser) parseOperandCommand(piped bool) (command, error) {
+	if piped {
+		return command{}, fmt.Errorf("%w: cannot pipe into a value", types.ErrInvalidTemplate)
+	}
+
+	arg, err := p.parseOperand()
+	if err != nil {
+		return command{}, err
+	}
+
+	return command{fn: nil, args: []node{arg}}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("%w: missing operand", types.ErrInvalidTemplate)
+	}
+
+	switch tok.typ {
+	case tokenString:
+		return textNode(tok.value), nil
+	case tokenField:
+		field, err := lookup(tok)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.arity != noArgs {
+			return nil, fmt.Errorf("%w: .%s expects arguments, use parentheses", types.ErrInvalidTemplate, tok.value)
+		}
+
+		return pipelineNode{{fn: field, args: nil}}, nil
+	case tokenLeftParen:
+		pipeline, err := p.parsePipeline()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok, ok := p.next(); !ok || tok.typ != tokenRightParen {
+			return nil, fmt.Errorf("%w: unclosed parenthesis", types.ErrInvalidTemplate)
+		}
+
+		return pipeline, nil
+	case tokenIdentifier, tokenPipe, tokenRightParen:
+	}
+
+	return nil, fmt.Errorf("%w: unexpected %q", types.ErrInvalidTemplate, tok.value)
+}
@@ -0,0 +1,232 @@
+package template_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/template"
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		text       string
+		statusCode int
+		expected   string
+	}{
+		{
+			name:     "empty",
+			text:     "",
+			expected: "",
+		},
+		{
+			name:     "literal",
+			text:     "Foo Bar",
+			expected: "Foo Bar",
+		},
+		{
+			name:     "single brace",
+			text:     "{Foo} }}",
+			expected: "{Foo} }}",
+		},
+		{
+			name:     "header",
+			text:     `{{ .Header "X-User" }}`,
+			expected: "John",
+		},
+		{
+			name:     "missing header",
+			text:     `{{ .Header "X-Missing" }}`,
+			expected: "",
+		},
+		{
+			name:     "Host header",
+			text:     `{{ .Header "Host" }}`,
+			expected: "example.com",
+		},
+		{
+			name:     "request fields",
+			text:     `{{ .Header "X-User" }}-{{ .Query "tenant" }}-{{ .Path }}-{{ .Method }}-{{ .RemoteAddr }}-{{ .Host }}`,
+			expected: "John-acme-/foo/b/r-GET-192.0.2.1:1234-example.com",
+		},
+		{
+			name:     "escaped path",
+			text:     `{{ .EscapedPath }}`,
+			expected: "/foo/b%2Fr",
+		},
+		{
+			name:       "status",
+			text:       `{{ .Status }}`,
+			statusCode: http.StatusNotFound,
+			expected:   "404",
+		},
+		{
+			name:     "status on request",
+			text:     `{{ .Status }}`,
+			expected: "",
+		},
+		{
+			name:     "functions",
+			text:     `{{ lower (.Header "X-User") }} {{ upper "foo" }} {{ trim "  bar " }}`,
+			expected: "john FOO bar",
+		},
+		{
+			name:     "pipeline",
+			text:     `{{ .Header "X-User" | lower | replace "j" "J" }}`,
+			expected: "John",
+		},
+		{
+			name:     "default",
+			text:     `{{ .Header "X-Missing" | default "anonymous" }} {{ default "anonymous" (.Header "X-User") }}`,
+			expected: "anonymous John",
+		},
+		{
+			name:     "encoding",
+			text:     `{{ base64 "foo:bar" }} {{ .Path | urlencode }} {{ pathescape "a b/c" }}`,
+			expected: "Zm9vOmJhcg== %2Ffoo%2Fb%2Fr a%20b%2Fc",
+		},
+		{
+			name:     "strings with delimiters and escapes",
+			text:     "{{ \"}}\" }} {{ \"a\\\"b\" }} {{ `c\\d` }}",
+			expected: `}} a"b c\d`,
+		},
+		{
+			name:     "escaped left delimiter",
+			text:     `{{ "{{" }} .Path }}`,
+			expected: "{{ .Path }}",
+		},
+		{
+			name:     "nested parentheses",
+			text:     `{{ upper (replace "o" "0" (lower "FOO")) }}`,
+			expected: "F00",
+		},
+		{
+			name:     "string pipeline",
+			text:     `{{ "Bar" | lower }}`,
+			expected: "bar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo/b%2Fr?tenant=acme", nil)
+			req.Header.Set("X-User", "John")
+
+			if test.statusCode != 0 {
+				req = req.WithContext(state.NewContext(req.Context(), &state.State{
+					OriginalHeader: nil,
+					StatusCode:     test.statusCode,
+				}))
+			}
+
+			tmpl, err := template.Parse(test.text)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, tmpl.Execute(req))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{
+			name:    "valid template",
+			text:    `{{ .Header "X-User" | default "anonymous" }}`,
+			wantErr: false,
+		},
+		{
+			name:    "unclosed action",
+			text:    `{{ .Path `,
+			wantErr: true,
+		},
+		{
+			name:    "empty action",
+			text:    `{{ }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			text:    `{{ foo "bar" }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			text:    `{{ .Foo }}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing argument",
+			text:    `{{ .Header }}`,
+			wantErr: true,
+		},
+		{
+			name:    "too many arguments",
+			text:    `{{ .Path "foo" }}`,
+			wantErr: true,
+		},
+		{
+			name:    "too many piped arguments",
+			text:    `{{ "foo" | lower "bar" }}`,
+			wantErr: true,
+		},
+		{
+			name:    "field with arguments without parentheses",
+			text:    `{{ lower .Header "X-User" }}`,
+			wantErr: true,
+		},
+		{
+			name:    "pipe into a value",
+			text:    `{{ .Path | "foo" }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			text:    `{{ "foo }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unclosed parenthesis",
+			text:    `{{ lower (.Path }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unexpected parenthesis",
+			text:    `{{ .Path ) }}`,
+			wantErr: true,
+		},
+		{
+			name:    "unexpected character",
+			text:    `{{ .Path ; }}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := template.Parse(test.text)
+			t.Log(err)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -12,14 +12,164 @@ type RuleType string
 const (
 	// Set will set the value of a header.
 	Set RuleType = "Set"
+	// SetIfAbsent will set the value of a header only if it is missing or empty.
+	SetIfAbsent RuleType = "SetIfAbsent"
+	// SetIfPresent will set the value of a header only if it already exists.
+	SetIfPresent RuleType = "SetIfPresent"
+	// Add will add values to a header, as separate header entries.
+	Add RuleType = "Add"
+	// Hash will replace a header, or set another one, with the hash of its value.
+	Hash RuleType = "Hash"
+	// HMAC will set a header to the signature of the request method, path and headers.
+	HMAC RuleType = "HMAC"
+	// VerifyHMAC will check the signature of the request method, path and headers.
+	VerifyHMAC RuleType = "VerifyHMAC"
+	// Require will check a request header, before the other request rules are applied.
+	Require RuleType = "Require"
+	// Encode will encode the values of a header.
+	Encode RuleType = "Encode"
+	// Decode will decode the values of a header.
+	Decode RuleType = "Decode"
+	// Reject will answer the request with an error, without forwarding it.
+	Reject RuleType = "Reject"
 	// Join will concatenate the values of headers.
 	Join RuleType = "Join"
 	// Delete will delete the value of a header.
 	Delete RuleType = "Del"
+	// DeleteValue will delete the values, or the elements of the values, of a header matching a regexp.
+	DeleteValue RuleType = "DelValue"
 	// Rename will rename a header.
 	Rename RuleType = "Rename"
 	// RewriteValueRule will replace the value of a header with the provided value.
 	RewriteValueRule RuleType = "RewriteValueRule"
+	// ClientIP will set a header to the address of the client, as forwarded by the trusted proxies.
+	ClientIP RuleType = "ClientIP"
+	// Copy will copy the values of a header to another one.
+	Copy RuleType = "Copy"
+	// Echo will copy request headers to the response.
+	Echo RuleType = "Echo"
+	// CookieSet will set the value of a request cookie.
+	CookieSet RuleType = "CookieSet"
+	// CookieDelete will delete request cookies.
+	CookieDelete RuleType = "CookieDelete"
+	// CookieRename will rename a request cookie.
+	CookieRename RuleType = "CookieRename"
+	// CookieAttributes will force attributes on the cookies set by the response.
+	CookieAttributes RuleType = "CookieAttributes"
+	// URLToHeader will set a header from a query parameter, the path or the fragment of the request URL.
+	URLToHeader RuleType = "URLToHeader"
+	// HeaderToURL will write a header into a query parameter or the path of the request URL.
+	HeaderToURL RuleType = "HeaderToURL"
+	// TraceContext will validate the W3C trace context of the request, or generate a new one.
+	TraceContext RuleType = "TraceContext"
+	// TraceConvert will translate the trace context of a propagation format to another.
+	TraceConvert RuleType = "TraceConvert"
+	// Forwarded will convert between the Forwarded and X-Forwarded-* headers, appending this hop.
+	Forwarded RuleType = "Forwarded"
+	// GenerateID will set a header to a generated identifier if it is missing.
+	GenerateID RuleType = "GenerateID"
+	// Keep will delete every header not listed.
+	Keep RuleType = "Keep"
+	// Allowlist is an alias of Keep.
+	Allowlist RuleType = "Allowlist"
+)
+
+// ConflictPolicy defines what to do when several headers are renamed to the same header.
+type ConflictPolicy string
+
+const (
+	// ConflictOverwrite keeps the values of the last header, sorted by name.
+	ConflictOverwrite ConflictPolicy = "Overwrite"
+	// ConflictAppend keeps the values of every header, sorted by name.
+	ConflictAppend ConflictPolicy = "Append"
+	// ConflictKeepFirst keeps the values of the first header, sorted by name.
+	ConflictKeepFirst ConflictPolicy = "KeepFirst"
+	// ConflictError does not apply the rule.
+	ConflictError ConflictPolicy = "Error"
+)
+
+// IDFormat defines the format of the identifiers generated by GenerateID.
+type IDFormat string
+
+const (
+	// IDUUIDv4 is a random UUID.
+	IDUUIDv4 IDFormat = "UUIDv4"
+	// IDUUIDv7 is a UUID ordered by creation time.
+	IDUUIDv7 IDFormat = "UUIDv7"
+	// IDULID is a lexicographically sortable identifier.
+	IDULID IDFormat = "ULID"
+	// IDHex is a random hexadecimal string.
+	IDHex IDFormat = "Hex"
+)
+
+// TraceFormat defines a trace context propagation format.
+type TraceFormat string
+
+const (
+	// TraceW3C is the W3C Trace Context traceparent header.
+	TraceW3C TraceFormat = "W3C"
+	// TraceB3 is the Zipkin B3 X-B3-TraceId, X-B3-SpanId and X-B3-Sampled headers.
+	TraceB3 TraceFormat = "B3"
+	// TraceB3Single is the Zipkin B3 single b3 header.
+	TraceB3Single TraceFormat = "B3Single"
+	// TraceJaeger is the Jaeger uber-trace-id header.
+	TraceJaeger TraceFormat = "Jaeger"
+	// TraceXRay is the AWS X-Ray X-Amzn-Trace-Id header.
+	TraceXRay TraceFormat = "XRay"
+	// TraceCloudTrace is the Google Cloud Trace X-Cloud-Trace-Context header.
+	TraceCloudTrace TraceFormat = "CloudTrace"
+)
+
+// HashAlgorithm defines the hash function used by Hash and HMAC.
+type HashAlgorithm string
+
+const (
+	// SHA256 is the SHA-256 hash function.
+	SHA256 HashAlgorithm = "SHA256"
+	// SHA512 is the SHA-512 hash function.
+	SHA512 HashAlgorithm = "SHA512"
+)
+
+// Encoding defines how values, like hashes, are written in headers.
+type Encoding string
+
+const (
+	// EncodingHex is the lowercase hexadecimal encoding.
+	EncodingHex Encoding = "Hex"
+	// EncodingBase64 is the standard base64 encoding, with padding.
+	EncodingBase64 Encoding = "Base64"
+	// EncodingBase64URL is the URL safe base64 encoding, without padding.
+	EncodingBase64URL Encoding = "Base64URL"
+	// EncodingURLQuery is the escaping of URL query parameters.
+	EncodingURLQuery Encoding = "URLQuery"
+	// EncodingURLPath is the escaping of URL path segments.
+	EncodingURLPath Encoding = "URLPath"
+	// EncodingMIMEWord is the RFC 2047 MIME encoded-word.
+	EncodingMIMEWord Encoding = "MIMEWord"
+)
+
+// FailurePolicy defines what to do with a request failing a check.
+type FailurePolicy string
+
+const (
+	// FailureLeave leaves the request unchanged.
+	FailureLeave FailurePolicy = "Leave"
+	// FailureDelete deletes the headers checked.
+	FailureDelete FailurePolicy = "Delete"
+	// FailureFlag sets a header telling whether the check failed.
+	FailureFlag FailurePolicy = "Flag"
+	// FailureReject answers the request with an error, without forwarding it.
+	FailureReject FailurePolicy = "Reject"
+)
+
+// ForwardedFormat defines a family of forwarding headers.
+type ForwardedFormat string
+
+const (
+	// ForwardedRFC7239 is the Forwarded header.
+	ForwardedRFC7239 ForwardedFormat = "Forwarded"
+	// ForwardedX is the X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host headers.
+	ForwardedX ForwardedFormat = "XForwarded"
 )
 
 // Rule struct so that we get traefik config.
@@ -28,13 +178,96 @@ type Rule struct {
 	HeaderPrefix string         `yaml:"HeaderPrefix"` // header prefix to find header
 	Name         string         `yaml:"Name"`         // rule name
 	Regexp       *regexp.Regexp `yaml:"-"`            // Used for rewrite, rename header matching
-	Sep          string         `yaml:"Sep"`          // separator to use for join
+	Sep          string         `yaml:"Sep"`          // separator to use for join, or to split values in DelValue
 	Type         RuleType       `yaml:"Type"`         // Differentiate rule types
 	Value        string         `yaml:"Value"`
 	ValueReplace string         `yaml:"ValueReplace"` // value used as replacement in rewrite
-	Values       []string       `yaml:"Values"`       // values to join
+	Values       []string       `yaml:"Values"`       // values to join or add
+	Deduplicate  bool           `yaml:"Deduplicate"`  // do not add values already present
+	OnConflict   ConflictPolicy `yaml:"OnConflict"`   // policy used when renaming several headers to the same one
+	Cookie       string         `yaml:"Cookie"`       // cookie name, or regex matching cookie names
+	Query        string         `yaml:"Query"`        // query parameter name
+	Path         string         `yaml:"Path"`         // regex matching the request path
+	Fragment     bool           `yaml:"Fragment"`     // use the URL fragment
+	RemoveSource bool           `yaml:"RemoveSource"` // remove the source once written, in the URL or in the headers
+	IDFormat     IDFormat       `yaml:"IDFormat"`     // format of the generated identifier
+	Length       int            `yaml:"Length"`       // length of the generated hexadecimal identifier
+	// if CopyToResponse is true, the request header is also set on the response.
+	CopyToResponse bool `yaml:"CopyToResponse"`
+	// if NewSpan is true, TraceContext replaces the span ID of the request by a new one for the hop.
+	NewSpan bool            `yaml:"NewSpan"`
+	Mirror  []TraceFormat   `yaml:"Mirror"` // trace formats the trace context is copied to
+	From    TraceFormat     `yaml:"From"`   // trace format TraceConvert reads
+	To      TraceFormat     `yaml:"To"`     // trace format TraceConvert writes
+	Format  ForwardedFormat `yaml:"Format"` // forwarding headers Forwarded writes
+	// if AppendHop is true, Forwarded appends this hop to the forwarding headers.
+	AppendHop bool `yaml:"AppendHop"`
+	// TrustedProxies are the addresses, or CIDRs, of the proxies ClientIP trusts the forwarding headers of.
+	TrustedProxies []string `yaml:"TrustedProxies"`
+	// Source is the header ClientIP reads the forwarded addresses from, the one the trusted proxies write.
+	Source string `yaml:"Source"`
+	// if StripUntrusted is true, ClientIP deletes the forwarding headers sent by a peer not trusted.
+	StripUntrusted bool          `yaml:"StripUntrusted"`
+	Algorithm      HashAlgorithm `yaml:"Algorithm"` // hash function of Hash and HMAC
+	Encoding       Encoding      `yaml:"Encoding"`  // encoding of the hash or signature
+	Salt           string        `yaml:"Salt"`      // prepended to the value hashed by Hash
+	// Secret, or the content of SecretFile, is the HMAC key.
+	Secret     string `yaml:"Secret"`
+	SecretFile string `yaml:"SecretFile"`
+	// SignedHeaders are the headers, in order, signed with the request method and path by HMAC.
+	SignedHeaders   []string          `yaml:"SignedHeaders"`
+	OnFailure       FailurePolicy     `yaml:"OnFailure"`       // policy applied to the requests failing a check
+	FlagHeader      string            `yaml:"FlagHeader"`      // header set to the result of the check by the Flag policy
+	StatusCode      int               `yaml:"StatusCode"`      // status code of the rejected requests
+	Body            string            `yaml:"Body"`            // body of the rejected requests
+	ResponseHeaders map[string]string `yaml:"ResponseHeaders"` // headers of the rejected requests
+	// Attributes are forced on the cookies set by the response.
+	Attributes *SetCookieAttributes `yaml:"Attributes"`
 	// if SetOnResponse is true, the header will be changed on the response. It will be on the request otherwise (default).
 	SetOnResponse bool `yaml:"SetOnResponse"`
+	// if FromRequest is true, the headers are read from the request when changing the response.
+	FromRequest bool `yaml:"FromRequest"`
+	// if Transformed is true, Echo reads the request headers after the request rules are applied.
+	// It reads them as received by the plugin otherwise (default).
+	Transformed bool `yaml:"Transformed"`
+	// if DropProtected is true, Keep also deletes the Host, Content-Length and Transfer-Encoding headers not listed.
+	DropProtected bool       `yaml:"DropProtected"`
+	When          *Condition `yaml:"When"` // condition to match for the rule to be applied
+}
+
+// SetCookieAttributes are the attributes forced on Set-Cookie headers, the empty ones are left unchanged.
+type SetCookieAttributes struct {
+	Secure   bool   `yaml:"Secure"`
+	HTTPOnly bool   `yaml:"HTTPOnly"`
+	SameSite string `yaml:"SameSite"` // Lax, Strict or None
+	Domain   string `yaml:"Domain"`
+	Path     string `yaml:"Path"`
+	MaxAge   int    `yaml:"MaxAge"` // in seconds, a negative value expires the cookie
+}
+
+// Condition restricts a rule to the requests (and responses) it matches.
+// Every field set must match, And, Or and Not allow combining conditions.
+type Condition struct {
+	Methods      []string         `yaml:"Methods"`      // request methods, one of them must match
+	Hosts        []string         `yaml:"Hosts"`        // request hosts, one of them must match
+	PathPrefix   string           `yaml:"PathPrefix"`   // prefix of the request path
+	PathRegexp   string           `yaml:"PathRegexp"`   // regexp matching the request path
+	Query        []FieldCondition `yaml:"Query"`        // conditions on query parameters
+	Headers      []FieldCondition `yaml:"Headers"`      // conditions on request headers
+	StatusCodes  []string         `yaml:"StatusCodes"`  // response status codes (e.g. 503, 4xx, 500-599)
+	ContentTypes []string         `yaml:"ContentTypes"` // response media types (e.g. application/json, text/*)
+	And          []Condition      `yaml:"And"`          // every condition must match
+	Or           []Condition      `yaml:"Or"`           // at least one condition must match
+	Not          *Condition       `yaml:"Not"`          // condition must not match
+}
+
+// FieldCondition matches a header or a query parameter.
+type FieldCondition struct {
+	Name   string `yaml:"Name"`   // header or query parameter name
+	Absent bool   `yaml:"Absent"` // if Absent is true, the field must not be present
+	Regexp string `yaml:"Regexp"` // regexp one of the values must match, the field only has to be present if empty
+	// if LongerThan is set, one of the values must be longer than LongerThan bytes.
+	LongerThan int `yaml:"LongerThan"`
 }
 
 var ErrMissingRequiredFields = errors.New("missing required fields")
@@ -43,9 +276,33 @@ var ErrInvalidRuleType = errors.New("invalid rule type")
 
 var ErrInvalidRegexp = errors.New("invalid regexp")
 
+var ErrInvalidOption = errors.New("invalid option")
+
+var ErrInvalidTemplate = errors.New("invalid template")
+
+var ErrInvalidCondition = errors.New("invalid condition")
+
+var ErrResponseOnly = errors.New("rule only applies to the response")
+
+var ErrRequestOnly = errors.New("rule only applies to the request")
+
 var ErrNotHTTPHijacker = errors.New("not an http.Hijacker")
 
 type Handler interface {
 	Validate() error
 	Handle(rw http.ResponseWriter, req *http.Request)
 }
+
+// Requirement is a validation-only handler, the requirements of every rule are checked before the request rules are
+// applied, and the request is rejected with every violation found.
+type Requirement interface {
+	Handler
+	// Check returns the violation of the requirement by the request, or nil if the request satisfies it.
+	Check(req *http.Request) *Violation
+}
+
+// Violation describes why a request does not satisfy a requirement.
+type Violation struct {
+	Name   string `json:"name"`   // name of the header
+	Reason string `json:"reason"` // why the header is invalid
+}
@@ -0,0 +1,86 @@
+package encoding
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// Encode returns the value in the given encoding, or an empty string if the encoding is unknown.
+func Encode(encoding types.Encoding, value []byte) string {
+	switch encoding {
+	case types.EncodingHex:
+		return hex.EncodeToString(value)
+	case types.EncodingBase64:
+		return base64.StdEncoding.EncodeToString(value)
+	case types.EncodingBase64URL:
+		return base64.RawURLEncoding.EncodeToString(value)
+	case types.EncodingURLQuery:
+		return url.QueryEscape(string(value))
+	case types.EncodingURLPath:
+		return url.PathEscape(string(value))
+	case types.EncodingMIMEWord:
+		return mime.QEncoding.Encode("utf-8", string(value))
+	}
+
+	return ""
+}
+
+// Decode returns the value decoded from the given encoding.
+// Base64 values are accepted with or without padding.
+func Decode(encoding types.Encoding, value string) ([]byte, error) {
+	var (
+		decoded []byte
+		err     error
+	)
+
+	switch encoding {
+	case types.EncodingHex:
+		decoded, err = hex.DecodeString(value)
+	case types.EncodingBase64:
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+	case types.EncodingBase64URL:
+		decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	case types.EncodingURLQuery:
+		var unescaped string
+		unescaped, err = url.QueryUnescape(value)
+		decoded = []byte(unescaped)
+	case types.EncodingURLPath:
+		var unescaped string
+		unescaped, err = url.PathUnescape(value)
+		decoded = []byte(unescaped)
+	case types.EncodingMIMEWord:
+		var word string
+		word, err = new(mime.WordDecoder).DecodeHeader(value)
+		decoded = []byte(word)
+	default:
+		return nil, fmt.Errorf("%w: Encoding %q", types.ErrInvalidOption, encoding)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", encoding, err)
+	}
+
+	return decoded, nil
+}
+
+// Supported reports whether the encoding is a known encoding.
+func Supported(encoding types.Encoding) bool {
+	switch encoding {
+	case types.EncodingHex, types.EncodingBase64, types.EncodingBase64URL,
+		types.EncodingURLQuery, types.EncodingURLPath, types.EncodingMIMEWord:
+		return true
+	}
+
+	return false
+}
+
+// Binary reports whether the encoding is a known encoding of binary values, like hashes.
+func Binary(encoding types.Encoding) bool {
+	return encoding == types.EncodingHex || encoding == types.EncodingBase64 || encoding == types.EncodingBase64URL
+}
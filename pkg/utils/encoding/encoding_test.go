@@ -0,0 +1,87 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/encoding"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding   types.Encoding
+		value      []byte
+		want       string
+		wantBinary bool
+	}{
+		{encoding: types.EncodingHex, value: []byte{0xfb, 0xff, 0x3e}, want: "fbff3e", wantBinary: true},
+		{encoding: types.EncodingBase64, value: []byte{0xfb, 0xff, 0x3e}, want: "+/8+", wantBinary: true},
+		{encoding: types.EncodingBase64URL, value: []byte{0xfb, 0xff, 0x3e}, want: "-_8-", wantBinary: true},
+		{encoding: types.EncodingURLQuery, value: []byte("a b/é"), want: "a+b%2F%C3%A9"},
+		{encoding: types.EncodingURLPath, value: []byte("a b/é"), want: "a%20b%2F%C3%A9"},
+		{encoding: types.EncodingMIMEWord, value: []byte("Café"), want: "=?utf-8?q?Caf=C3=A9?="},
+		{encoding: "Base32", value: []byte("foo"), want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.encoding), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, encoding.Encode(test.encoding, test.value))
+			assert.Equal(t, test.want != "", encoding.Supported(test.encoding))
+			assert.Equal(t, test.wantBinary, encoding.Binary(test.encoding))
+
+			if test.want != "" {
+				decoded, err := encoding.Decode(test.encoding, test.want)
+				assert.NoError(t, err)
+				assert.Equal(t, string(test.value), string(decoded))
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		encoding types.Encoding
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "base64 with padding", encoding: types.EncodingBase64, value: "Zm9vYg==", want: "foob"},
+		{name: "base64 without padding", encoding: types.EncodingBase64, value: "Zm9vYg", want: "foob"},
+		{name: "base64 URL with padding", encoding: types.EncodingBase64URL, value: "-_8-Zg==", want: "\xfb\xff\x3ef"},
+		{name: "invalid base64", encoding: types.EncodingBase64, value: "Zm9v!", wantErr: true},
+		{name: "invalid hex", encoding: types.EncodingHex, value: "fg", wantErr: true},
+		{name: "invalid URL query", encoding: types.EncodingURLQuery, value: "%zz", wantErr: true},
+		{
+			name:     "MIME B encoding",
+			encoding: types.EncodingMIMEWord,
+			value:    "=?UTF-8?B?Q2Fmw6k=?= au lait",
+			want:     "Café au lait",
+		},
+		{name: "MIME unknown charset", encoding: types.EncodingMIMEWord, value: "=?koi8-r?q?foo?=", wantErr: true},
+		{name: "unknown encoding", encoding: "Base32", value: "MZXW6===", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			decoded, err := encoding.Decode(test.encoding, test.value)
+			if test.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, string(decoded))
+		})
+	}
+}
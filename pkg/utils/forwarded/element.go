@@ -0,0 +1,120 @@
+package forwarded
+
+import (
+	"net"
+	"strings"
+)
+
+// Element is a forwarded-element of a Forwarded header, the parameters of a proxy hop.
+type Element struct {
+	For        string // node the request came from
+	By         string // node the request came in on
+	Proto      string // protocol of the request received by the proxy
+	Host       string // Host header of the request received by the proxy
+	Extensions []Pair // other parameters, in their received order
+}
+
+// Pair is a forwarded-pair of a Forwarded header.
+type Pair struct {
+	Name  string
+	Value string
+}
+
+// String returns the forwarded-element, quoting the values that are not tokens.
+func (e Element) String() string {
+	pairs := make([]string, 0, len(e.Extensions)+4) //nolint:mnd // for, by, proto and host.
+
+	for _, pair := range e.pairs() {
+		if pair.Value != "" {
+			pairs = append(pairs, pair.Name+"="+quote(pair.Value))
+		}
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+func (e Element) pairs() []Pair {
+	return append([]Pair{
+		{Name: "for", Value: e.For},
+		{Name: "by", Value: e.By},
+		{Name: "proto", Value: e.Proto},
+		{Name: "host", Value: e.Host},
+	}, e.Extensions...)
+}
+
+// set sets the parameter of the element, the parameter names being case-insensitive.
+func set(element *Element, name, value string) {
+	switch strings.ToLower(name) {
+	case "for":
+		element.For = value
+	case "by":
+		element.By = value
+	case "proto":
+		element.Proto = value
+	case "host":
+		element.Host = value
+	default:
+		element.Extensions = append(element.Extensions, Pair{Name: name, Value: value})
+	}
+}
+
+// Format returns the Forwarded header value of the elements.
+func Format(elements []Element) string {
+	values := make([]string, 0, len(elements))
+
+	for _, element := range elements {
+		if value := element.String(); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// Node returns the node of an address, as found in X-Forwarded-For or a request RemoteAddr,
+// enclosing IPv6 addresses in brackets.
+func Node(address string) string {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return "[" + address + "]"
+	}
+
+	return address
+}
+
+// Address returns the address of a node without its port and brackets, as used in X-Forwarded-For.
+func Address(node string) string {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+}
+
+// quote returns the value if it is a token, the quoted-string of the value otherwise.
+func quote(value string) string {
+	if isToken(value) {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func isToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, char := range []byte(value) {
+		if !isTokenChar(char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTokenChar reports whether the character is a tchar of RFC 7230.
+func isTokenChar(char byte) bool {
+	return ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || ('0' <= char && char <= '9') ||
+		strings.IndexByte("!#$%&'*+-.^_`|~", char) >= 0
+}
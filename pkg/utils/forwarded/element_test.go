@@ -0,0 +1,77 @@
+package forwarded_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/forwarded"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		elements []forwarded.Element
+		want     string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name: "tokens",
+			elements: []forwarded.Element{
+				{For: "192.0.2.60", By: "203.0.113.43", Proto: "http", Host: "example.com"},
+			},
+			want: "for=192.0.2.60;by=203.0.113.43;proto=http;host=example.com",
+		},
+		{
+			name: "quoted values",
+			elements: []forwarded.Element{
+				{For: "[2001:db8:cafe::17]:4711"},
+				{For: "192.0.2.43:47011", Extensions: []forwarded.Pair{{Name: "secret", Value: `a"b`}}},
+				{},
+			},
+			want: `for="[2001:db8:cafe::17]:4711", for="192.0.2.43:47011";secret="a\"b"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			value := forwarded.Format(test.elements)
+			assert.Equal(t, test.want, value)
+
+			elements, ok := forwarded.Parse([]string{value})
+			assert.Equal(t, true, ok)
+			assert.Equal(t, forwarded.Format(elements), value)
+		})
+	}
+}
+
+func TestNodeAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		address string
+		node    string
+	}{
+		{address: "192.0.2.43", node: "192.0.2.43"},
+		{address: "2001:db8:cafe::17", node: "[2001:db8:cafe::17]"},
+		{address: "unknown", node: "unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.node, forwarded.Node(test.address))
+			assert.Equal(t, test.address, forwarded.Address(test.node))
+		})
+	}
+
+	assert.Equal(t, "192.0.2.43", forwarded.Address("192.0.2.43:47011"))
+	assert.Equal(t, "2001:db8:cafe::17", forwarded.Address("[2001:db8:cafe::17]:4711"))
+}
@@ -0,0 +1,129 @@
+package forwarded
+
+import (
+	"strings"
+)
+
+// Parse parses the values of Forwarded headers, it returns false if they are malformed.
+func Parse(values []string) ([]Element, bool) {
+	parser := &parser{input: strings.Join(values, ","), pos: 0}
+
+	var elements []Element
+
+	element := Element{For: "", By: "", Proto: "", Host: "", Extensions: nil}
+	empty := true
+
+	for {
+		parser.skipSpaces()
+
+		if parser.done() || parser.input[parser.pos] == ',' {
+			if !empty {
+				elements = append(elements, element)
+			}
+
+			if parser.done() {
+				return elements, true
+			}
+
+			parser.pos++
+			element, empty = Element{For: "", By: "", Proto: "", Host: "", Extensions: nil}, true
+
+			continue
+		}
+
+		if parser.input[parser.pos] == ';' {
+			parser.pos++
+
+			continue
+		}
+
+		name, value, valid := parser.pair()
+		if !valid {
+			return nil, false
+		}
+
+		set(&element, name, value)
+
+		empty = false
+	}
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// pair parses a forwarded-pair: token "=" value, followed by a separator.
+func (p *parser) pair() (string, string, bool) {
+	name := p.token()
+	if name == "" || p.done() || p.input[p.pos] != '=' {
+		return "", "", false
+	}
+
+	p.pos++
+
+	var (
+		value string
+		valid bool
+	)
+
+	if !p.done() && p.input[p.pos] == '"' {
+		value, valid = p.quotedString()
+	} else {
+		value = p.token()
+		valid = value != ""
+	}
+
+	p.skipSpaces()
+
+	if !valid || (!p.done() && p.input[p.pos] != ';' && p.input[p.pos] != ',') {
+		return "", "", false
+	}
+
+	return name, value, true
+}
+
+func (p *parser) token() string {
+	start := p.pos
+
+	for !p.done() && isTokenChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+// quotedString parses a quoted-string, unescaping its quoted-pairs.
+func (p *parser) quotedString() (string, bool) {
+	var value strings.Builder
+
+	for p.pos++; !p.done(); p.pos++ {
+		switch char := p.input[p.pos]; char {
+		case '"':
+			p.pos++
+
+			return value.String(), true
+		case '\\':
+			p.pos++
+			if p.done() {
+				return "", false
+			}
+
+			value.WriteByte(p.input[p.pos])
+		default:
+			value.WriteByte(char)
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,83 @@
+package forwarded_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/forwarded"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []string
+		want   []forwarded.Element
+		wantOk bool
+	}{
+		{
+			name:   "empty",
+			values: nil,
+			want:   nil,
+			wantOk: true,
+		},
+		{
+			name:   "single element",
+			values: []string{`for=192.0.2.60;proto=http;by=203.0.113.43`},
+			want: []forwarded.Element{
+				{For: "192.0.2.60", By: "203.0.113.43", Proto: "http"},
+			},
+			wantOk: true,
+		},
+		{
+			name:   "quoted IPv6 and case-insensitive names",
+			values: []string{`For="[2001:db8:cafe::17]:4711"; Host="example.com"`},
+			want: []forwarded.Element{
+				{For: "[2001:db8:cafe::17]:4711", Host: "example.com"},
+			},
+			wantOk: true,
+		},
+		{
+			name:   "several elements and headers",
+			values: []string{`for=192.0.2.43, for=198.51.100.17`, `for=unknown;secret="a\"b,c;d"`},
+			want: []forwarded.Element{
+				{For: "192.0.2.43"},
+				{For: "198.51.100.17"},
+				{For: "unknown", Extensions: []forwarded.Pair{{Name: "secret", Value: `a"b,c;d`}}},
+			},
+			wantOk: true,
+		},
+		{
+			name:   "missing value",
+			values: []string{`for=`},
+			wantOk: false,
+		},
+		{
+			name:   "unquoted IPv6",
+			values: []string{`for=[2001:db8:cafe::17]`},
+			wantOk: false,
+		},
+		{
+			name:   "unterminated quoted string",
+			values: []string{`for="192.0.2.43`},
+			wantOk: false,
+		},
+		{
+			name:   "missing separator",
+			values: []string{`for=192.0.2.43 proto=http`},
+			wantOk: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			elements, ok := forwarded.Parse(test.values)
+
+			assert.Equal(t, test.wantOk, ok)
+			assert.Equal(t, test.want, elements)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package header
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NameRegexp compiles a regex matching header names.
+// Header names are matched as a whole, case-insensitively.
+func NameRegexp(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("header name %q: %w", expr, err)
+	}
+
+	return re, nil
+}
@@ -0,0 +1,63 @@
+package header_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+func TestNameRegexp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		expr    string
+		header  string
+		matched bool
+	}{
+		{
+			name:    "same name",
+			expr:    "X-Test",
+			header:  "X-Test",
+			matched: true,
+		},
+		{
+			name:    "other case",
+			expr:    "x-test",
+			header:  "X-Test",
+			matched: true,
+		},
+		{
+			name:    "longer name",
+			expr:    "X-Test",
+			header:  "X-Test-Id",
+			matched: false,
+		},
+		{
+			name:    "alternation matched as a whole",
+			expr:    "X-Foo|X-Bar",
+			header:  "X-Foo-Bar",
+			matched: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			re, err := header.NameRegexp(test.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.matched, re.MatchString(test.header))
+		})
+	}
+}
+
+func TestNameRegexpInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := header.NameRegexp("(")
+	assert.Error(t, err)
+}
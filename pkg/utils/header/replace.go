@@ -0,0 +1,24 @@
+package header
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Replace replaces the values of a request header.
+// The Host has a single value, it is replaced by the first one.
+func Replace(req *http.Request, header string, values []string) {
+	Delete(req, header)
+
+	if strings.EqualFold(header, "Host") {
+		if len(values) > 0 {
+			req.Host = values[0]
+		}
+
+		return
+	}
+
+	for _, value := range values {
+		req.Header.Add(header, value)
+	}
+}
@@ -0,0 +1,56 @@
+package header_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		header         string
+		values         []string
+		expectedValues []string
+		expectedHost   string
+	}{
+		{
+			name:           "Replace header",
+			header:         "Foo",
+			values:         []string{"Bar", "Baz"},
+			expectedValues: []string{"Bar", "Baz"},
+			expectedHost:   "example.com",
+		},
+		{
+			name:         "Replace header without values",
+			header:       "Foo",
+			expectedHost: "example.com",
+		},
+		{
+			name:           "Replace Host header",
+			header:         "Host",
+			values:         []string{"example.org", "example.net"},
+			expectedValues: []string{"example.org"},
+			expectedHost:   "example.org",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.Header.Set("Foo", "Old")
+
+			header.Replace(req, test.header, test.values)
+
+			assert.Equal(t, test.expectedHost, req.Host)
+			assert.Equal(t, test.expectedValues, header.Values(req, test.header))
+		})
+	}
+}
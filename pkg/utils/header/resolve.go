@@ -0,0 +1,30 @@
+package header
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Resolve checks if the value starts with the given prefix, and then proceeds
+// to read the existing header (after stripping the prefix) to return as value.
+// The value is returned as is otherwise.
+func Resolve(req *http.Request, value, prefix string) string {
+	if prefix == "" || !strings.HasPrefix(value, prefix) {
+		return value
+	}
+
+	header := strings.TrimPrefix(value, prefix)
+	// If the resulting value after removing the prefix is empty,
+	// we return the actual value,
+	// which is the prefix itself.
+	// This is because doing a req.Header.Get("") would not fly well.
+	if header == "" {
+		return value
+	}
+
+	if strings.EqualFold(header, "Host") {
+		return req.Host
+	}
+
+	return req.Header.Get(header)
+}
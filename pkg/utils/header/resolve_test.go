@@ -0,0 +1,75 @@
+package header_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		value         string
+		prefix        string
+		expectedValue string
+	}{
+		{
+			name:          "no prefix",
+			value:         "^Foo",
+			prefix:        "",
+			expectedValue: "^Foo",
+		},
+		{
+			name:          "value without prefix",
+			value:         "Foo",
+			prefix:        "^",
+			expectedValue: "Foo",
+		},
+		{
+			name:          "header reference",
+			value:         "^Foo",
+			prefix:        "^",
+			expectedValue: "Bar",
+		},
+		{
+			name:          "multi-character prefix",
+			value:         "header:Foo",
+			prefix:        "header:",
+			expectedValue: "Bar",
+		},
+		{
+			name:          "missing header reference",
+			value:         "^X-Missing",
+			prefix:        "^",
+			expectedValue: "",
+		},
+		{
+			name:          "Host reference",
+			value:         "^Host",
+			prefix:        "^",
+			expectedValue: "example.com",
+		},
+		{
+			name:          "prefix only",
+			value:         "^",
+			prefix:        "^",
+			expectedValue: "^",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.Header.Set("Foo", "Bar")
+
+			assert.Equal(t, test.expectedValue, header.Resolve(req, test.value, test.prefix))
+		})
+	}
+}
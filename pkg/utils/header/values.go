@@ -0,0 +1,18 @@
+package header
+
+import (
+	"net/http"
+	"strings"
+)
+
+func Values(req *http.Request, header string) []string {
+	if strings.EqualFold(header, "Host") {
+		if req.Host == "" {
+			return nil
+		}
+
+		return []string{req.Host}
+	}
+
+	return req.Header.Values(header)
+}
@@ -0,0 +1,63 @@
+package header_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		header         string
+		host           string
+		expectedValues []string
+	}{
+		{
+			name:           "Values of header",
+			header:         "Foo",
+			host:           "example.com",
+			expectedValues: []string{"Bar", "Baz"},
+		},
+		{
+			name:           "Values of canonicalized header",
+			header:         "foo",
+			host:           "example.com",
+			expectedValues: []string{"Bar", "Baz"},
+		},
+		{
+			name:   "Values of missing header",
+			header: "X-Missing",
+			host:   "example.com",
+		},
+		{
+			name:           "Values of Host header",
+			header:         "Host",
+			host:           "example.com",
+			expectedValues: []string{"example.com"},
+		},
+		{
+			name:   "Values of empty Host header",
+			header: "Host",
+			host:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.Header.Add("Foo", "Bar")
+			req.Header.Add("Foo", "Baz")
+			req.Host = test.host
+
+			assert.Equal(t, test.expectedValues, header.Values(req, test.header))
+		})
+	}
+}
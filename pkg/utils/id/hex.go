@@ -0,0 +1,18 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// Hex returns a random hexadecimal string of the given length.
+func Hex(length int) (string, error) {
+	// an extra character is decoded for odd lengths.
+	random := make([]byte, hex.DecodedLen(length+1))
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("generating hex identifier: %w", err)
+	}
+
+	return hex.EncodeToString(random)[:length], nil
+}
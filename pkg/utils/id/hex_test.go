@@ -0,0 +1,44 @@
+package id_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/utils/id"
+)
+
+func TestHex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		length int
+	}{
+		{
+			name:   "even length",
+			length: 32,
+		},
+		{
+			name:   "odd length",
+			length: 7,
+		},
+		{
+			name:   "empty",
+			length: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := id.Hex(test.length)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.length, len(value))
+			assert.Equal(t, true, regexp.MustCompile(`^[0-9a-f]*$`).MatchString(value))
+		})
+	}
+}
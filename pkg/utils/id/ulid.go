@@ -0,0 +1,59 @@
+package id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+const (
+	// timestampSize is the size in bytes of the millisecond timestamp starting UUIDv7 and ULID.
+	timestampSize = 6
+	// ulidLength is the number of characters of an encoded ULID.
+	ulidLength = 26
+	// crockfordAlphabet is the base32 alphabet used by ULID.
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	bitsPerChar       = 5
+	bitsPerByte       = 8
+	// paddingBits are the leading zero bits encoded before the 128 bits of the ULID.
+	paddingBits = ulidLength*bitsPerChar - uuidSize*bitsPerByte
+)
+
+// ULID returns a lexicographically sortable identifier, see https://github.com/ulid/spec.
+func ULID(now time.Time) (string, error) {
+	var ulid [uuidSize]byte
+	if _, err := rand.Read(ulid[timestampSize:]); err != nil {
+		return "", fmt.Errorf("generating ULID: %w", err)
+	}
+
+	putTimestamp(ulid[:timestampSize], now)
+
+	encoded := make([]byte, ulidLength)
+
+	for index := range encoded {
+		var char byte
+
+		for bit := index*bitsPerChar - paddingBits; bit < (index+1)*bitsPerChar-paddingBits; bit++ {
+			char <<= 1
+
+			if bit >= 0 {
+				shift := uint(bitsPerByte - 1 - bit%bitsPerByte) //nolint:gosec // the shift is between 0 and 7.
+				char |= (ulid[bit/bitsPerByte] >> shift) & 1
+			}
+		}
+
+		encoded[index] = crockfordAlphabet[char]
+	}
+
+	return string(encoded), nil
+}
+
+// putTimestamp writes the Unix time in milliseconds as a big-endian 48 bits integer.
+func putTimestamp(dst []byte, now time.Time) {
+	millis := uint64(now.UnixMilli()) //nolint:gosec // times before 1970 are not supported.
+
+	for index := timestampSize - 1; index >= 0; index-- {
+		dst[index] = byte(millis)
+		millis >>= bitsPerByte
+	}
+}
@@ -0,0 +1,27 @@
+package id_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/utils/id"
+)
+
+func TestULID(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(1469918176385)
+
+	ulid, err := id.ULID(now)
+	require.NoError(t, err)
+
+	assert.Equal(t, true, regexp.MustCompile(`^01ARYZ6S41[0-9A-HJKMNP-TV-Z]{16}$`).MatchString(ulid))
+
+	later, err := id.ULID(now.Add(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, ulid < later)
+}
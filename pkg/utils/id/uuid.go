@@ -0,0 +1,55 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// uuidSize is the size of a UUID in bytes.
+	uuidSize = 16
+	// versionIndex is the index of the byte holding the UUID version in its high nibble.
+	versionIndex = 6
+	// variantIndex is the index of the byte holding the UUID variant in its high bits.
+	variantIndex = 8
+	versionMask  = 0xf0
+	version4     = 0x40
+	version7     = 0x70
+	variantMask  = 0xc0
+	// variantRFC is the variant defined by RFC 9562, the 0b10 high bits.
+	variantRFC = 0x80
+)
+
+// UUIDv4 returns a random UUID, as defined by RFC 9562.
+func UUIDv4() (string, error) {
+	var uuid [uuidSize]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", fmt.Errorf("generating UUIDv4: %w", err)
+	}
+
+	return formatUUID(uuid, version4), nil
+}
+
+// UUIDv7 returns a UUID ordered by creation time, as defined by RFC 9562.
+func UUIDv7(now time.Time) (string, error) {
+	var uuid [uuidSize]byte
+	if _, err := rand.Read(uuid[timestampSize:]); err != nil {
+		return "", fmt.Errorf("generating UUIDv7: %w", err)
+	}
+
+	putTimestamp(uuid[:timestampSize], now)
+
+	return formatUUID(uuid, version7), nil
+}
+
+// formatUUID sets the version and the variant of the UUID and returns its string representation.
+func formatUUID(uuid [uuidSize]byte, version byte) string {
+	uuid[versionIndex] = uuid[versionIndex]&^versionMask | version
+	uuid[variantIndex] = uuid[variantIndex]&^variantMask | variantRFC
+
+	encoded := hex.EncodeToString(uuid[:])
+
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
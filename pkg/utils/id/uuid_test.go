@@ -0,0 +1,43 @@
+package id_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/utils/id"
+)
+
+func TestUUIDv4(t *testing.T) {
+	t.Parallel()
+
+	uuid, err := id.UUIDv4()
+	require.NoError(t, err)
+
+	assert.Equal(t, true, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).
+		MatchString(uuid))
+
+	other, err := id.UUIDv4()
+	require.NoError(t, err)
+
+	assert.Equal(t, false, uuid == other)
+}
+
+func TestUUIDv7(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(1469918176385)
+
+	uuid, err := id.UUIDv7(now)
+	require.NoError(t, err)
+
+	assert.Equal(t, true, regexp.MustCompile(`^01563df3-6481-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).
+		MatchString(uuid))
+
+	later, err := id.UUIDv7(now.Add(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, uuid < later)
+}
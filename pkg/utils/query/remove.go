@@ -0,0 +1,28 @@
+package query
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Remove removes every value of the parameter from the raw query, keeping the
+// order and the encoding of the other parameters.
+func Remove(rawQuery, name string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	kept := make([]string, 0, len(params))
+
+	for _, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil && unescaped == name {
+			continue
+		}
+
+		kept = append(kept, param)
+	}
+
+	return strings.Join(kept, "&")
+}
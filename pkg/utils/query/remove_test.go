@@ -0,0 +1,58 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/query"
+)
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		param    string
+		expected string
+	}{
+		{
+			name:     "remove parameter",
+			rawQuery: "z=1&tenant=acme&a=2",
+			param:    "tenant",
+			expected: "z=1&a=2",
+		},
+		{
+			name:     "remove every value",
+			rawQuery: "tenant=acme&a=2&tenant=other&tenant",
+			param:    "tenant",
+			expected: "a=2",
+		},
+		{
+			name:     "remove encoded parameter",
+			rawQuery: "tenant+id=acme&a=%2F",
+			param:    "tenant id",
+			expected: "a=%2F",
+		},
+		{
+			name:     "remove missing parameter",
+			rawQuery: "a=1;b=2",
+			param:    "tenant",
+			expected: "a=1;b=2",
+		},
+		{
+			name:     "remove from empty query",
+			rawQuery: "",
+			param:    "tenant",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, query.Remove(test.rawQuery, test.param))
+		})
+	}
+}
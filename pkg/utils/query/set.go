@@ -0,0 +1,21 @@
+package query
+
+import (
+	"net/url"
+)
+
+// Set replaces the values of the parameter in the raw query, the new values
+// being appended after the other parameters.
+func Set(rawQuery, name string, values ...string) string {
+	rawQuery = Remove(rawQuery, name)
+
+	for _, value := range values {
+		if rawQuery != "" {
+			rawQuery += "&"
+		}
+
+		rawQuery += url.QueryEscape(name) + "=" + url.QueryEscape(value)
+	}
+
+	return rawQuery
+}
@@ -0,0 +1,64 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/query"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		param    string
+		values   []string
+		expected string
+	}{
+		{
+			name:     "set parameter",
+			rawQuery: "page=2",
+			param:    "tenant",
+			values:   []string{"acme"},
+			expected: "page=2&tenant=acme",
+		},
+		{
+			name:     "set parameter on empty query",
+			rawQuery: "",
+			param:    "tenant",
+			values:   []string{"acme"},
+			expected: "tenant=acme",
+		},
+		{
+			name:     "replace parameter",
+			rawQuery: "tenant=other&page=2",
+			param:    "tenant",
+			values:   []string{"acme"},
+			expected: "page=2&tenant=acme",
+		},
+		{
+			name:     "set several values",
+			rawQuery: "page=2",
+			param:    "tenant",
+			values:   []string{"acme", "other"},
+			expected: "page=2&tenant=acme&tenant=other",
+		},
+		{
+			name:     "encode parameter",
+			rawQuery: "",
+			param:    "tenant id",
+			values:   []string{"acme & co/fr"},
+			expected: "tenant+id=acme+%26+co%2Ffr",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, query.Set(test.rawQuery, test.param, test.values...))
+		})
+	}
+}
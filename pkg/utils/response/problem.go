@@ -0,0 +1,37 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// problem is a RFC 9457 problem details document.
+type problem struct {
+	Type          string            `json:"type"`
+	Title         string            `json:"title"`
+	Status        int               `json:"status"`
+	Detail        string            `json:"detail"`
+	InvalidParams []types.Violation `json:"invalid-params"` //nolint:tagliatelle // RFC 9457 example member.
+}
+
+// WriteProblem answers the request with a 400 Bad Request problem details document listing the violations.
+func WriteProblem(rw http.ResponseWriter, violations []types.Violation) {
+	body, err := json.Marshal(problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(http.StatusBadRequest),
+		Status:        http.StatusBadRequest,
+		Detail:        fmt.Sprintf("%d invalid request header(s)", len(violations)),
+		InvalidParams: violations,
+	})
+	if err != nil {
+		Write(rw, http.StatusBadRequest, "")
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/problem+json")
+	Write(rw, http.StatusBadRequest, string(body))
+}
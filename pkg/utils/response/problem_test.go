@@ -0,0 +1,28 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+)
+
+func TestWriteProblem(t *testing.T) {
+	t.Parallel()
+
+	rw := httptest.NewRecorder()
+
+	response.WriteProblem(rw, []types.Violation{
+		{Name: "X-Api-Key", Reason: "missing"},
+		{Name: "Content-Type", Reason: "not one of application/json"},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, "application/problem+json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, `{"type":"about:blank","title":"Bad Request","status":400,`+
+		`"detail":"2 invalid request header(s)","invalid-params":[{"name":"X-Api-Key","reason":"missing"},`+
+		`{"name":"Content-Type","reason":"not one of application/json"}]}`, rw.Body.String())
+}
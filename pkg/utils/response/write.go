@@ -0,0 +1,18 @@
+package response
+
+import (
+	"net/http"
+)
+
+// Write answers the request with the status code and the body, as plain text unless a Content-Type is set.
+func Write(rw http.ResponseWriter, statusCode int, body string) {
+	if body != "" && rw.Header().Get("Content-Type") == "" {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	rw.WriteHeader(statusCode)
+
+	if body != "" {
+		_, _ = rw.Write([]byte(body))
+	}
+}
@@ -0,0 +1,54 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/response"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		body            string
+		contentType     string
+		wantContentType string
+	}{
+		{
+			name:            "no body",
+			wantContentType: "",
+		},
+		{
+			name:            "plain text body",
+			body:            "forbidden",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "body with content type",
+			body:            `{"error":"forbidden"}`,
+			contentType:     "application/json",
+			wantContentType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			rw := httptest.NewRecorder()
+			if test.contentType != "" {
+				rw.Header().Set("Content-Type", test.contentType)
+			}
+
+			response.Write(rw, http.StatusForbidden, test.body)
+
+			assert.Equal(t, http.StatusForbidden, rw.Code)
+			assert.Equal(t, test.body, rw.Body.String())
+			assert.Equal(t, test.wantContentType, rw.Header().Get("Content-Type"))
+		})
+	}
+}
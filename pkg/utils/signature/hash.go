@@ -0,0 +1,29 @@
+package signature
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// NewHash returns the constructor of the hash function, or nil if the algorithm is unknown.
+func NewHash(algorithm types.HashAlgorithm) func() hash.Hash {
+	switch algorithm {
+	case types.SHA256:
+		return sha256.New
+	case types.SHA512:
+		return sha512.New
+	}
+
+	return nil
+}
+
+// Sum returns the hash of the value, the algorithm must be known.
+func Sum(algorithm types.HashAlgorithm, value []byte) []byte {
+	digest := NewHash(algorithm)()
+	digest.Write(value)
+
+	return digest.Sum(nil)
+}
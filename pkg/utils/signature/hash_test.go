@@ -0,0 +1,39 @@
+package signature_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/signature"
+)
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		algorithm types.HashAlgorithm
+		want      string
+	}{
+		{
+			algorithm: types.SHA256,
+			want:      "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			algorithm: types.SHA512,
+			want: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a" +
+				"2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.algorithm), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, hex.EncodeToString(signature.Sum(test.algorithm, []byte("abc"))))
+		})
+	}
+
+	assert.Equal(t, true, signature.NewHash("MD5") == nil)
+}
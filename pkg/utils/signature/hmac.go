@@ -0,0 +1,52 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/header"
+)
+
+// Secret returns the HMAC key of the rule, read from SecretFile if it is set.
+// The trailing line break of the file is not part of the key.
+func Secret(rule *types.Rule) ([]byte, error) {
+	if rule.SecretFile == "" {
+		return []byte(rule.Secret), nil
+	}
+
+	if rule.Secret != "" {
+		return nil, fmt.Errorf("%w: only one of Secret and SecretFile can be set", types.ErrInvalidOption)
+	}
+
+	secret, err := os.ReadFile(rule.SecretFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: SecretFile: %w", types.ErrInvalidOption, err)
+	}
+
+	return []byte(strings.TrimRight(string(secret), "\r\n")), nil
+}
+
+// Canonical returns the signed representation of the request: its method, its escaped path, then a
+// "name:values" line for each header, in the given order, with the lowercase name and comma separated values.
+func Canonical(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers)+2) //nolint:mnd // method and path.
+	lines = append(lines, req.Method, req.URL.EscapedPath())
+
+	for _, name := range headers {
+		lines = append(lines, strings.ToLower(name)+":"+strings.Join(header.Values(req, name), ","))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Sign returns the HMAC of the canonical representation of the request, the algorithm must be known.
+func Sign(algorithm types.HashAlgorithm, secret []byte, req *http.Request, headers []string) []byte {
+	mac := hmac.New(NewHash(algorithm), secret)
+	mac.Write([]byte(Canonical(req, headers)))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,94 @@
+package signature_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/tests/require"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/signature"
+)
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		rule    types.Rule
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "secret",
+			rule: types.Rule{Secret: "s3cr3t"},
+			want: "s3cr3t",
+		},
+		{
+			name: "secret file",
+			rule: types.Rule{SecretFile: secretFile},
+			want: "s3cr3t",
+		},
+		{
+			name:    "missing secret file",
+			rule:    types.Rule{SecretFile: filepath.Join(t.TempDir(), "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "secret and secret file",
+			rule:    types.Rule{Secret: "s3cr3t", SecretFile: secretFile},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			secret, err := signature.Secret(&test.rule)
+			if test.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.want, string(secret))
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://example.com/a%2Fb/c?d=e", nil)
+	require.NoError(t, err)
+
+	req.Header.Add("X-User", "alice")
+	req.Header.Add("X-Roles", "admin")
+	req.Header.Add("X-Roles", "dev")
+
+	want := "POST\n/a%2Fb/c\nx-roles:admin,dev\nhost:example.com\nx-user:alice\nx-missing:"
+	assert.Equal(t, want, signature.Canonical(req, []string{"X-Roles", "Host", "x-user", "X-Missing"}))
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.com/foo", nil)
+	require.NoError(t, err)
+
+	req.Header.Set("X-User", "alice")
+
+	// HMAC-SHA256 of "GET\n/foo\nx-user:alice" with the key "Jefe", computed with openssl.
+	want := "3d52f528529343ccdd23625c7f707c91bb438fb4ed16369af5c29fc6dad61769"
+	got := hex.EncodeToString(signature.Sign(types.SHA256, []byte("Jefe"), req, []string{"X-User"}))
+
+	assert.Equal(t, want, got)
+}
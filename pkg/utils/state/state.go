@@ -0,0 +1,31 @@
+package state
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// State holds the data of a request shared between the plugin and the rule handlers.
+type State struct {
+	// OriginalHeader is a copy of the request headers before any rule is applied, it is nil if not needed.
+	OriginalHeader http.Header
+	// StatusCode is the status code written by the upstream, or by a rule answering the request,
+	// it is 0 until the response headers are written.
+	StatusCode int
+}
+
+// NewContext returns a copy of ctx carrying the given state.
+func NewContext(ctx context.Context, s *State) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromRequest returns the state of the request, or an empty state if there is none.
+func FromRequest(req *http.Request) *State {
+	if s, ok := req.Context().Value(contextKey{}).(*State); ok {
+		return s
+	}
+
+	return &State{OriginalHeader: nil, StatusCode: 0}
+}
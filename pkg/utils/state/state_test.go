@@ -0,0 +1,23 @@
+package state_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/state"
+)
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+	assert.Equal(t, &state.State{OriginalHeader: nil, StatusCode: 0}, state.FromRequest(req))
+
+	s := &state.State{OriginalHeader: req.Header.Clone(), StatusCode: http.StatusOK}
+	req = req.WithContext(state.NewContext(req.Context(), s))
+
+	s.StatusCode = http.StatusNotFound
+	assert.Equal(t, http.StatusNotFound, state.FromRequest(req).StatusCode)
+}
@@ -0,0 +1,134 @@
+package trace
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/utils/id"
+)
+
+const (
+	// traceIDLength and spanIDLength are the number of hexadecimal characters of the identifiers.
+	traceIDLength = 32
+	spanIDLength  = 16
+	// maxTracestateMembers is the maximum number of list members of a tracestate header.
+	maxTracestateMembers = 32
+	// sampledFlag is the traceparent flag set when the caller may have recorded the trace.
+	sampledFlag = 0x01
+)
+
+var (
+	traceparentRegexp = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})(-.*)?$`)
+	tracestateKey     = regexp.MustCompile(
+		`^(?:[a-z][_0-9a-z\-*/]{0,255}|[a-z0-9][_0-9a-z\-*/]{0,240}@[a-z][_0-9a-z\-*/]{0,13})$`)
+	tracestateValue = regexp.MustCompile(`^[\x20-\x2b\x2d-\x3c\x3e-\x7e]{0,255}[\x21-\x2b\x2d-\x3c\x3e-\x7e]$`)
+)
+
+// Context identifies a span of a trace.
+type Context struct {
+	TraceID string // 32 lowercase hexadecimal characters
+	SpanID  string // 16 lowercase hexadecimal characters
+	Sampled bool
+}
+
+// New returns a sampled context with a new trace ID and span ID, it returns false if the random source failed.
+func New() (Context, bool) {
+	ctx := Context{TraceID: "", SpanID: "", Sampled: true}
+
+	traceID, err := id.Hex(traceIDLength)
+	if err != nil {
+		return ctx, false
+	}
+
+	ctx.TraceID = traceID
+
+	return ctx.Child()
+}
+
+// Child returns the context with a new span ID, it returns false if the random source failed.
+func (c Context) Child() (Context, bool) {
+	spanID, err := id.Hex(spanIDLength)
+	if err != nil {
+		return c, false
+	}
+
+	c.SpanID = spanID
+
+	return c, true
+}
+
+// Valid reports whether the identifiers are well formed and not all zeros.
+func (c Context) Valid() bool {
+	return validID(c.TraceID, traceIDLength) && validID(c.SpanID, spanIDLength)
+}
+
+// ParseTraceparent parses a W3C traceparent header value, it returns false if the value is invalid.
+func ParseTraceparent(value string) (Context, bool) {
+	ctx := Context{TraceID: "", SpanID: "", Sampled: false}
+
+	match := traceparentRegexp.FindStringSubmatch(value)
+	if match == nil || match[1] == "ff" || (match[1] == "00" && match[5] != "") {
+		return ctx, false
+	}
+
+	flags, err := strconv.ParseUint(match[4], 16, 8)
+	if err != nil {
+		return ctx, false
+	}
+
+	ctx.TraceID, ctx.SpanID, ctx.Sampled = match[2], match[3], flags&sampledFlag != 0
+
+	return ctx, ctx.Valid()
+}
+
+// Traceparent returns the W3C traceparent header value of the context.
+func (c Context) Traceparent() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + c.TraceID + "-" + c.SpanID + "-" + flags
+}
+
+// ValidTracestate reports whether the W3C tracestate header value is well formed.
+func ValidTracestate(value string) bool {
+	members := strings.Split(value, ",")
+	if len(members) > maxTracestateMembers {
+		return false
+	}
+
+	keys := make(map[string]bool, len(members))
+
+	for _, member := range members {
+		member = strings.Trim(member, " \t")
+		if member == "" {
+			continue
+		}
+
+		key, val, found := strings.Cut(member, "=")
+		if !found || keys[key] || !tracestateKey.MatchString(key) || !tracestateValue.MatchString(val) {
+			return false
+		}
+
+		keys[key] = true
+	}
+
+	return true
+}
+
+// validID reports whether the identifier has the given number of lowercase hexadecimal characters, not all zeros.
+func validID(value string, length int) bool {
+	if len(value) != length || strings.Trim(value, "0") == "" {
+		return false
+	}
+
+	for _, char := range value {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+
+	return true
+}
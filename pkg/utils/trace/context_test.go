@@ -0,0 +1,179 @@
+package trace_test
+
+import (
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		wantValid bool
+		want      trace.Context
+	}{
+		{
+			name:      "sampled",
+			value:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantValid: true,
+			want: trace.Context{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: true,
+			},
+		},
+		{
+			name:      "not sampled",
+			value:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-02",
+			wantValid: true,
+			want: trace.Context{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: false,
+			},
+		},
+		{
+			name:      "future version with extra fields",
+			value:     "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-will-be",
+			wantValid: true,
+			want: trace.Context{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: true,
+			},
+		},
+		{
+			name:  "version 00 with extra fields",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		},
+		{
+			name:  "forbidden version",
+			value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "uppercase",
+			value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01",
+		},
+		{
+			name:  "zero trace ID",
+			value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "zero span ID",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		},
+		{
+			name:  "short trace ID",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		},
+		{
+			name:  "empty",
+			value: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, valid := trace.ParseTraceparent(test.value)
+
+			assert.Equal(t, test.wantValid, valid)
+
+			if test.wantValid {
+				assert.Equal(t, test.want, ctx)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	ctx, valid := trace.New()
+
+	assert.Equal(t, true, valid)
+	assert.Equal(t, true, ctx.Valid())
+	assert.Equal(t, true, ctx.Sampled)
+
+	child, valid := ctx.Child()
+
+	assert.Equal(t, true, valid)
+	assert.Equal(t, ctx.TraceID, child.TraceID)
+	assert.Equal(t, false, ctx.SpanID == child.SpanID)
+}
+
+func TestTraceparent(t *testing.T) {
+	t.Parallel()
+
+	ctx := trace.Context{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Sampled: true,
+	}
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ctx.Traceparent())
+
+	ctx.Sampled = false
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", ctx.Traceparent())
+}
+
+func TestValidTracestate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{
+			name:  "single member",
+			value: "congo=t61rcWkgMzE",
+			want:  true,
+		},
+		{
+			name:  "several members with spaces",
+			value: "rojo=00f067aa0ba902b7 , congo=t61rcWkgMzE,, tenant@vendor=value",
+			want:  true,
+		},
+		{
+			name:  "missing value",
+			value: "congo",
+			want:  false,
+		},
+		{
+			name:  "invalid key",
+			value: "Congo=t61rcWkgMzE",
+			want:  false,
+		},
+		{
+			name:  "invalid value",
+			value: "congo=t61r=cWkgMzE",
+			want:  false,
+		},
+		{
+			name:  "duplicated key",
+			value: "congo=1,congo=2",
+			want:  false,
+		},
+		{
+			name: "too many members",
+			value: "a0=0,a1=1,a2=2,a3=3,a4=4,a5=5,a6=6,a7=7,a8=8,a9=9,b0=0,b1=1,b2=2,b3=3,b4=4,b5=5," +
+				"b6=6,b7=7,b8=8,b9=9,c0=0,c1=1,c2=2,c3=3,c4=4,c5=5,c6=6,c7=7,c8=8,c9=9,d0=0,d1=1,d2=2",
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, trace.ValidTracestate(test.value))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package trace
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+const (
+	// b3SamplingField is the index of the optional sampling state of a b3 header.
+	b3SamplingField = 2
+	// b3MaxFields and jaegerFields are the number of fields of b3 and uber-trace-id headers.
+	b3MaxFields  = 4
+	jaegerFields = 4
+)
+
+// Decode returns the context propagated by the headers in the given format, it returns false if the headers are
+// missing or invalid.
+func Decode(format types.TraceFormat, headers http.Header) (Context, bool) {
+	switch format {
+	case types.TraceW3C:
+		if traceparents := headers.Values("Traceparent"); len(traceparents) == 1 {
+			return ParseTraceparent(traceparents[0])
+		}
+	case types.TraceB3:
+		return decodeB3(headers)
+	case types.TraceB3Single:
+		return decodeB3Single(headers.Get("B3"))
+	case types.TraceJaeger:
+		return decodeJaeger(headers.Get("Uber-Trace-Id"))
+	case types.TraceXRay:
+		return decodeXRay(headers.Get("X-Amzn-Trace-Id"))
+	case types.TraceCloudTrace:
+		return decodeCloudTrace(headers.Get("X-Cloud-Trace-Context"))
+	}
+
+	return Context{TraceID: "", SpanID: "", Sampled: false}, false
+}
+
+// Headers returns the names of the headers propagating the context in the given format.
+func Headers(format types.TraceFormat) []string {
+	switch format {
+	case types.TraceW3C:
+		return []string{"Traceparent"}
+	case types.TraceB3:
+		return []string{"X-B3-Traceid", "X-B3-Spanid", "X-B3-Parentspanid", "X-B3-Sampled", "X-B3-Flags"}
+	case types.TraceB3Single:
+		return []string{"B3"}
+	case types.TraceJaeger:
+		return []string{"Uber-Trace-Id"}
+	case types.TraceXRay:
+		return []string{"X-Amzn-Trace-Id"}
+	case types.TraceCloudTrace:
+		return []string{"X-Cloud-Trace-Context"}
+	}
+
+	return nil
+}
+
+// newContext returns the context of the identifiers, left padded with zeros as B3 and Jaeger allow shorter ones.
+func newContext(traceID, spanID string, sampled bool) (Context, bool) {
+	ctx := Context{
+		TraceID: leftPad(strings.ToLower(traceID), traceIDLength),
+		SpanID:  leftPad(strings.ToLower(spanID), spanIDLength),
+		Sampled: sampled,
+	}
+
+	return ctx, ctx.Valid()
+}
+
+func leftPad(value string, length int) string {
+	if len(value) >= length {
+		return value
+	}
+
+	return strings.Repeat("0", length-len(value)) + value
+}
+
+// decodeB3 decodes the X-B3-* headers, the debug flag implies the sampling decision.
+func decodeB3(headers http.Header) (Context, bool) {
+	sampled := b3Sampled(headers.Get("X-B3-Sampled")) || headers.Get("X-B3-Flags") == "1"
+
+	return newContext(headers.Get("X-B3-Traceid"), headers.Get("X-B3-Spanid"), sampled)
+}
+
+// decodeB3Single decodes a b3 header: {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}, the last two being optional.
+func decodeB3Single(value string) (Context, bool) {
+	fields := strings.Split(value, "-")
+	if len(fields) < b3SamplingField || len(fields) > b3MaxFields {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	sampled := len(fields) > b3SamplingField && (b3Sampled(fields[b3SamplingField]) || fields[b3SamplingField] == "d")
+
+	return newContext(fields[0], fields[1], sampled)
+}
+
+func b3Sampled(value string) bool {
+	return value == "1" || strings.EqualFold(value, "true")
+}
+
+// decodeJaeger decodes an uber-trace-id header: {trace-id}:{span-id}:{parent-span-id}:{flags}, which may be URL
+// encoded.
+func decodeJaeger(value string) (Context, bool) {
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	fields := strings.Split(value, ":")
+	if len(fields) != jaegerFields {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	flags, err := strconv.ParseUint(fields[3], 16, 8)
+	if err != nil {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	return newContext(fields[0], fields[1], flags&sampledFlag != 0)
+}
+
+// decodeXRay decodes a X-Amzn-Trace-Id header: Root=1-{epoch}-{unique};Parent={span};Sampled={0|1}, the fields
+// being in any order.
+func decodeXRay(value string) (Context, bool) {
+	var traceID, spanID string
+
+	sampled := false
+
+	for _, field := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(strings.TrimSpace(field), "=")
+
+		switch key {
+		case "Root":
+			parts := strings.Split(val, "-")
+			if len(parts) == 3 && parts[0] == "1" && len(parts[1]) == xrayEpochLength {
+				traceID = parts[1] + parts[2]
+			}
+		case "Parent":
+			spanID = val
+		case "Sampled":
+			sampled = val == "1"
+		}
+	}
+
+	if len(traceID) != traceIDLength || len(spanID) != spanIDLength {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	return newContext(traceID, spanID, sampled)
+}
+
+// decodeCloudTrace decodes a X-Cloud-Trace-Context header: {trace-id}/{decimal span-id};o={0|1}.
+func decodeCloudTrace(value string) (Context, bool) {
+	ids, options, _ := strings.Cut(value, ";")
+
+	traceID, decimalSpanID, found := strings.Cut(ids, "/")
+	if !found || len(traceID) != traceIDLength {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	spanID, err := strconv.ParseUint(decimalSpanID, 10, 64)
+	if err != nil {
+		return Context{TraceID: "", SpanID: "", Sampled: false}, false
+	}
+
+	return newContext(traceID, strconv.FormatUint(spanID, 16), options == "o=1")
+}
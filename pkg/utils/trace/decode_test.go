@@ -0,0 +1,201 @@
+package trace_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	sampled := trace.Context{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Sampled: true,
+	}
+	notSampled := sampled
+	notSampled.Sampled = false
+
+	tests := []struct {
+		name      string
+		format    types.TraceFormat
+		headers   http.Header
+		wantValid bool
+		want      trace.Context
+	}{
+		{
+			name:      "W3C",
+			format:    types.TraceW3C,
+			headers:   http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+			wantValid: true,
+			want:      sampled,
+		},
+		{
+			name:   "B3",
+			format: types.TraceB3,
+			headers: http.Header{
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+				"X-B3-Spanid":  {"00f067aa0ba902b7"},
+				"X-B3-Sampled": {"1"},
+			},
+			wantValid: true,
+			want:      sampled,
+		},
+		{
+			name:   "B3 with a 64 bits trace ID and the debug flag",
+			format: types.TraceB3,
+			headers: http.Header{
+				"X-B3-Traceid": {"a3ce929d0e0e4736"},
+				"X-B3-Spanid":  {"00f067aa0ba902b7"},
+				"X-B3-Flags":   {"1"},
+			},
+			wantValid: true,
+			want: trace.Context{
+				TraceID: "0000000000000000a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: true,
+			},
+		},
+		{
+			name:   "B3 without span ID",
+			format: types.TraceB3,
+			headers: http.Header{
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+			},
+		},
+		{
+			name:      "B3 single",
+			format:    types.TraceB3Single,
+			headers:   http.Header{"B3": {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0-05e3ac9a4f6e3b90"}},
+			wantValid: true,
+			want:      notSampled,
+		},
+		{
+			name:      "B3 single without sampling state",
+			format:    types.TraceB3Single,
+			headers:   http.Header{"B3": {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7"}},
+			wantValid: true,
+			want:      notSampled,
+		},
+		{
+			name:    "B3 single with only the sampling state",
+			format:  types.TraceB3Single,
+			headers: http.Header{"B3": {"0"}},
+		},
+		{
+			name:      "Jaeger",
+			format:    types.TraceJaeger,
+			headers:   http.Header{"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"}},
+			wantValid: true,
+			want:      sampled,
+		},
+		{
+			name:      "Jaeger URL encoded with short identifiers",
+			format:    types.TraceJaeger,
+			headers:   http.Header{"Uber-Trace-Id": {"a3ce929d0e0e4736%3Af067aa0ba902b7%3A0%3A3"}},
+			wantValid: true,
+			want: trace.Context{
+				TraceID: "0000000000000000a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: true,
+			},
+		},
+		{
+			name:    "Jaeger with invalid flags",
+			format:  types.TraceJaeger,
+			headers: http.Header{"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:z"}},
+		},
+		{
+			name:   "X-Ray",
+			format: types.TraceXRay,
+			headers: http.Header{
+				"X-Amzn-Trace-Id": {"Self=1-5759e988-bd862e3fe1be46a994272793;Sampled=1;" +
+					"Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7"},
+			},
+			wantValid: true,
+			want:      sampled,
+		},
+		{
+			name:    "X-Ray without parent",
+			format:  types.TraceXRay,
+			headers: http.Header{"X-Amzn-Trace-Id": {"Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Sampled=1"}},
+		},
+		{
+			name:      "Cloud Trace",
+			format:    types.TraceCloudTrace,
+			headers:   http.Header{"X-Cloud-Trace-Context": {"4bf92f3577b34da6a3ce929d0e0e4736/67667974448284343;o=1"}},
+			wantValid: true,
+			want:      sampled,
+		},
+		{
+			name:    "Cloud Trace with hexadecimal span ID",
+			format:  types.TraceCloudTrace,
+			headers: http.Header{"X-Cloud-Trace-Context": {"4bf92f3577b34da6a3ce929d0e0e4736/00f067aa0ba902b7;o=1"}},
+		},
+		{
+			name:    "missing headers",
+			format:  types.TraceW3C,
+			headers: http.Header{},
+		},
+		{
+			name:    "unknown format",
+			format:  "OpenTracing",
+			headers: http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, valid := trace.Decode(test.format, test.headers)
+
+			assert.Equal(t, test.wantValid, valid)
+
+			if test.wantValid {
+				assert.Equal(t, test.want, ctx)
+			}
+		})
+	}
+}
+
+func TestDecodeEncode(t *testing.T) {
+	t.Parallel()
+
+	ctx := trace.Context{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Sampled: true,
+	}
+
+	for _, format := range []types.TraceFormat{
+		types.TraceW3C, types.TraceB3, types.TraceB3Single, types.TraceJaeger, types.TraceXRay, types.TraceCloudTrace,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			decoded, valid := trace.Decode(format, trace.Encode(format, ctx))
+
+			assert.Equal(t, true, valid)
+			assert.Equal(t, ctx.Traceparent(), decoded.Traceparent())
+
+			for name := range trace.Encode(format, ctx) {
+				assert.Equalf(t, true, contains(trace.Headers(format), name), "%q is not listed", name)
+			}
+		})
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
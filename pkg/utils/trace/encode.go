@@ -0,0 +1,64 @@
+package trace
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tomMoulard/htransformation/pkg/types"
+)
+
+// xrayEpochLength is the number of hexadecimal characters of the trace ID
+// holding the epoch of the X-Ray trace ID.
+const xrayEpochLength = 8
+
+// Encode returns the headers propagating the context in the given format, or nil if the format is unknown.
+func Encode(format types.TraceFormat, ctx Context) http.Header {
+	headers := make(http.Header)
+
+	switch format {
+	case types.TraceW3C:
+		headers.Set("Traceparent", ctx.Traceparent())
+	case types.TraceB3:
+		headers.Set("X-B3-Traceid", ctx.TraceID)
+		headers.Set("X-B3-Spanid", ctx.SpanID)
+		headers.Set("X-B3-Sampled", ctx.sampledFlag())
+	case types.TraceB3Single:
+		headers.Set("B3", ctx.TraceID+"-"+ctx.SpanID+"-"+ctx.sampledFlag())
+	case types.TraceJaeger:
+		headers.Set("Uber-Trace-Id", ctx.TraceID+":"+ctx.SpanID+":0:"+ctx.sampledFlag())
+	case types.TraceXRay:
+		headers.Set("X-Amzn-Trace-Id", "Root=1-"+ctx.TraceID[:xrayEpochLength]+"-"+ctx.TraceID[xrayEpochLength:]+
+			";Parent="+ctx.SpanID+";Sampled="+ctx.sampledFlag())
+	case types.TraceCloudTrace:
+		spanID, err := strconv.ParseUint(ctx.SpanID, 16, 64)
+		if err != nil {
+			return nil
+		}
+
+		headers.Set("X-Cloud-Trace-Context",
+			ctx.TraceID+"/"+strconv.FormatUint(spanID, 10)+";o="+ctx.sampledFlag())
+	default:
+		return nil
+	}
+
+	return headers
+}
+
+// sampledFlag returns "1" if the context is sampled, "0" otherwise.
+func (c Context) sampledFlag() string {
+	if c.Sampled {
+		return "1"
+	}
+
+	return "0"
+}
+
+// Supported reports whether the format is a known trace format.
+func Supported(format types.TraceFormat) bool {
+	switch format {
+	case types.TraceW3C, types.TraceB3, types.TraceB3Single, types.TraceJaeger, types.TraceXRay, types.TraceCloudTrace:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package trace_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tomMoulard/htransformation/pkg/tests/assert"
+	"github.com/tomMoulard/htransformation/pkg/types"
+	"github.com/tomMoulard/htransformation/pkg/utils/trace"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  types.TraceFormat
+		sampled bool
+		want    http.Header
+	}{
+		{
+			name:    "W3C",
+			format:  types.TraceW3C,
+			sampled: true,
+			want: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+		},
+		{
+			name:    "B3",
+			format:  types.TraceB3,
+			sampled: true,
+			want: http.Header{
+				"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+				"X-B3-Spanid":  {"00f067aa0ba902b7"},
+				"X-B3-Sampled": {"1"},
+			},
+		},
+		{
+			name:    "B3 single",
+			format:  types.TraceB3Single,
+			sampled: false,
+			want: http.Header{
+				"B3": {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0"},
+			},
+		},
+		{
+			name:    "Jaeger",
+			format:  types.TraceJaeger,
+			sampled: true,
+			want: http.Header{
+				"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+			},
+		},
+		{
+			name:    "X-Ray",
+			format:  types.TraceXRay,
+			sampled: true,
+			want: http.Header{
+				"X-Amzn-Trace-Id": {"Root=1-4bf92f35-77b34da6a3ce929d0e0e4736;Parent=00f067aa0ba902b7;Sampled=1"},
+			},
+		},
+		{
+			name:    "Cloud Trace",
+			format:  types.TraceCloudTrace,
+			sampled: false,
+			want: http.Header{
+				"X-Cloud-Trace-Context": {"4bf92f3577b34da6a3ce929d0e0e4736/67667974448284343;o=0"},
+			},
+		},
+		{
+			name:   "unknown format",
+			format: "OpenTracing",
+			want:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := trace.Context{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Sampled: test.sampled,
+			}
+
+			assert.Equal(t, test.want, trace.Encode(test.format, ctx))
+			assert.Equal(t, test.want != nil, trace.Supported(test.format))
+		})
+	}
+}